@@ -9,6 +9,7 @@ CFLAGS="-g -O0" ./configure \
     --with-http_gzip_static_module \
     --with-http_sub_module \
     --with-http_v2_module \
+    --add-module=/build/ngx_brotli/static \
     --prefix=/etc/nginx \
     --http-log-path=/var/log/nginx/access.log \
     --error-log-path=/var/log/nginx/error.log \
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -23,6 +25,29 @@ const (
 	namespaceEnvVar      = "WATCH_NAMESPACE"
 	warpPathEnvVar       = "WARP_PATH"
 	deploymentNameEnvVar = "DEPLOYMENT_NAME"
+	// debounceWindowEnvVar and debounceMaxDelayEnvVar define how changes are collected before the warp menu is
+	// generated, e.g. "2s".
+	debounceWindowEnvVar   = "WARP_DEBOUNCE_WINDOW"
+	debounceMaxDelayEnvVar = "WARP_DEBOUNCE_MAX_DELAY"
+	// menuBindAddressEnvVar defines the address of the HTTP endpoint serving the warp menu, "0" disables it.
+	menuBindAddressEnvVar = "WARP_MENU_BIND_ADDRESS"
+	// DefaultDebounceWindow is the time without changes after which the warp menu is generated.
+	DefaultDebounceWindow = 2 * time.Second
+	// DefaultDebounceMaxDelay is the longest time a change waits for the warp menu generation.
+	DefaultDebounceMaxDelay = 10 * time.Second
+	// DefaultMenuBindAddress only accepts connections from the nginx in the same pod. The other containers of the pod
+	// already use the ports 8080 to 8083.
+	DefaultMenuBindAddress = "127.0.0.1:8084"
+	// MenuBindAddressDisabled disables the HTTP endpoint serving the warp menu.
+	MenuBindAddressDisabled = "0"
+	// WarpStatusConfigMap contains the machine-readable status of the last warp menu generation.
+	WarpStatusConfigMap = "k8s-ces-warp-status"
+	// WarpSourceCacheConfigMap contains the last successful result of every warp menu source.
+	WarpSourceCacheConfigMap = "k8s-ces-warp-source-cache"
+	// DefaultShrinkGracePeriod is the time after which the shrink guard accepts a shrink that persists.
+	DefaultShrinkGracePeriod = 10 * time.Minute
+	// DefaultSourceTimeout is the time after which reading a source is aborted.
+	DefaultSourceTimeout = 10 * time.Second
 )
 
 var (
@@ -37,7 +62,109 @@ type Configuration struct {
 	Sources []Source
 	Target  string
 	Order   Order
-	Support []SupportSource
+	// NestedCategories nests categories whose title is a path, e.g. "Development Apps/CI". Without it a title
+	// containing "/" is a category of its own.
+	NestedCategories bool
+	Support          []SupportSource
+	Categories       map[string]CategoryConfig
+	Merge            MergeConfig
+	Sorting          SortingConfig
+	// MenuFormat is the format of the generated menu.json, either "v1" (default) or "v2".
+	MenuFormat  string
+	ShrinkGuard ShrinkGuardConfig
+	SourceCache SourceCacheConfig
+	// Transforms change the entries of the sources. The rules are applied in the configured order.
+	Transforms []TransformRule
+	// Hosts contains the host names that get their own menu, e.g. "partner.example.com". Transform rules select the
+	// entries of a host with the condition Host.
+	Hosts []string
+	// TemplateVariables contains the global config keys that can be used in hrefs and descriptions of entries in
+	// addition to "fqdn" and "domain", e.g. "{{ .fqdn }}".
+	TemplateVariables []string
+	Compression       CompressionConfig
+}
+
+// CompressionConfig defines the pre-compressed copies of the warp menu files. A gzip compressed copy is always written.
+type CompressionConfig struct {
+	// Brotli additionally writes a brotli compressed copy, e.g. "menu.json.br".
+	Brotli bool
+}
+
+// TransformRule changes all entries matching its conditions.
+type TransformRule struct {
+	Match TransformMatch
+	// Rename replaces the display name of the entry.
+	Rename string
+	// Category moves the entry to the category with the given title or path, e.g. "Development Apps/CI".
+	Category string
+	// Hide removes the entry from the warp menu.
+	Hide bool
+	// Target replaces the target of the entry, e.g. "newWindow".
+	Target string `json:",omitempty"`
+	// Order sets the weight of the entry used in categories with the sort mode "weight".
+	Order *int
+}
+
+// TransformMatch contains the conditions of a TransformRule. Conditions that are not set match every entry.
+type TransformMatch struct {
+	// Host is the host name of the menu the rule applies to, e.g. "partner.example.com". A host starting with "!"
+	// matches all menus except the one of the host. Rules without host apply to the default menu and all host menus.
+	Host string
+	// Source is the type of the source the entry was read from, e.g. "dogus".
+	Source string
+	// Category is the title or path of the category of the entry.
+	Category string
+	// Name is a regular expression matching the display name of the entry.
+	Name string
+	// Tag is a tag of the dogu the entry was created from.
+	Tag string
+}
+
+// SourceCacheConfig defines how the last successful result of every source is kept.
+type SourceCacheConfig struct {
+	// Persist stores the results in a configmap, so they survive restarts of the controller.
+	Persist bool
+}
+
+// ShrinkGuardConfig defines when a newly generated warp menu is considered broken because too many entries
+// disappeared.
+type ShrinkGuardConfig struct {
+	// Disabled turns the shrink guard off.
+	Disabled bool
+	// MaxShrinkPercent is the percentage of entries that may disappear in a single generation. Defaults to 50.
+	MaxShrinkPercent int
+	// GracePeriod is the time after which a shrink that persists is accepted, e.g. "30m". Defaults to
+	// DefaultShrinkGracePeriod.
+	GracePeriod string `json:",omitempty"`
+}
+
+// SortingConfig defines how the entries of the categories are sorted.
+type SortingConfig struct {
+	// Locale is used to sort entries alphabetically, e.g. "de". The root locale is used by default.
+	Locale string
+	// Mode is the sort mode of all categories, either "alphabetical" (default), "weight" or "source".
+	Mode string
+	// Categories contains the sort mode by category title or path.
+	Categories map[string]string
+	// Weights contains the weight by entry id, e.g. the dogu name. Entries with a higher weight come first.
+	Weights map[string]int
+}
+
+// MergeConfig defines how duplicate entries of different sources are resolved.
+type MergeConfig struct {
+	// Key identifies duplicate entries, either "href" (default) or "id".
+	Key string
+	// Prefer is the source whose entry is kept, either "dogu" (default) or "external".
+	Prefer string
+}
+
+// CategoryConfig contains the metadata of a warp menu category. Child categories are configured by their path, e.g.
+// "Development Apps/CI".
+type CategoryConfig struct {
+	Icon        string
+	Description string
+	Collapsed   bool
+	Hidden      bool
 }
 
 // Source in global config
@@ -45,6 +172,8 @@ type Source struct {
 	Path string
 	Type string
 	Tag  string
+	// Timeout limits the time to read the source, e.g. "5s". Defaults to DefaultSourceTimeout.
+	Timeout string `json:",omitempty"`
 }
 
 // SupportSource for SupportEntries from yaml
@@ -52,6 +181,13 @@ type SupportSource struct {
 	Identifier string
 	External   bool
 	Href       string
+	// Target overrides the target derived from External, e.g. "newWindow".
+	Target string `json:",omitempty"`
+	// Order is the weight of the entry if the support category is sorted by weight.
+	Order int
+	// ValidFrom and ValidUntil limit the time in which the entry is shown, e.g. "2026-01-31T00:00:00Z".
+	ValidFrom  time.Time
+	ValidUntil time.Time
 }
 
 // ReadConfiguration reads the service discovery configuration. Either from file in development mode with environment
@@ -79,6 +215,11 @@ func readWarpConfigFromFile(path string) (*Configuration, error) {
 		return nil, fmt.Errorf("failed to unmarshal configuration %s: %w", path, err)
 	}
 
+	err = config.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+
 	return config, nil
 }
 
@@ -100,9 +241,39 @@ func readWarpConfigFromCluster(ctx context.Context, client client.Client, namesp
 		return nil, fmt.Errorf("failed to unmarshal yaml from warp config: %w", err)
 	}
 
+	err = conf.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid warp config: %w", err)
+	}
+
 	return conf, nil
 }
 
+// targetNames contains the names of the targets a warp menu entry can be opened in.
+var targetNames = []string{"self", "external", "newWindow", "embedded"}
+
+// validate rejects values that would only fail when the warp menu is generated, like unknown targets.
+func (c *Configuration) validate() error {
+	for _, support := range c.Support {
+		if err := validateTarget(support.Target); err != nil {
+			return fmt.Errorf("invalid support entry %s: %w", support.Identifier, err)
+		}
+	}
+	for i, rule := range c.Transforms {
+		if err := validateTarget(rule.Target); err != nil {
+			return fmt.Errorf("invalid transform rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateTarget(target string) error {
+	if target == "" || slices.Contains(targetNames, target) {
+		return nil
+	}
+	return fmt.Errorf("unknown target %q: must be one of \"self\", \"external\", \"newWindow\", \"embedded\"", target)
+}
+
 func ReadWatchNamespace() (string, error) {
 	watchNamespace, found := os.LookupEnv(namespaceEnvVar)
 	if !found {
@@ -132,3 +303,44 @@ func ReadDeploymentName() (string, error) {
 
 	return deploymentName, nil
 }
+
+// ReadDebounceWindow returns the debounce window of the warp menu generation or DefaultDebounceWindow if it is not set.
+func ReadDebounceWindow() (time.Duration, error) {
+	return readDuration(debounceWindowEnvVar, DefaultDebounceWindow)
+}
+
+// ReadDebounceMaxDelay returns the maximum delay of the warp menu generation or DefaultDebounceMaxDelay if it is not
+// set.
+func ReadDebounceMaxDelay() (time.Duration, error) {
+	return readDuration(debounceMaxDelayEnvVar, DefaultDebounceMaxDelay)
+}
+
+// ReadMenuBindAddress returns the address of the HTTP endpoint serving the warp menu or DefaultMenuBindAddress if it
+// is not set. MenuBindAddressDisabled disables the endpoint.
+func ReadMenuBindAddress() string {
+	address, found := os.LookupEnv(menuBindAddressEnvVar)
+	if !found || address == "" {
+		return DefaultMenuBindAddress
+	}
+	logger.Info(fmt.Sprintf("found %s: [%s]", menuBindAddressEnvVar, address))
+
+	return address
+}
+
+func readDuration(envVar string, defaultValue time.Duration) (time.Duration, error) {
+	value, found := os.LookupEnv(envVar)
+	if !found || value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration from environment variable [%s]: %w", envVar, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration from environment variable [%s] must not be negative", envVar)
+	}
+	logger.Info(fmt.Sprintf("found %s: [%s]", envVar, duration))
+
+	return duration, nil
+}
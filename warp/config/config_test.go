@@ -8,8 +8,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"os"
+	"path/filepath"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
+	"time"
 )
 
 //go:embed testdata/k8s_config.yaml
@@ -121,6 +123,72 @@ func Test_readWarpConfigFromFile(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		assert.NotNil(t, config)
+		expectedCategories := map[string]CategoryConfig{
+			"Development Apps":    {Icon: "code", Description: "Tools for software development"},
+			"Development Apps/CI": {Collapsed: true},
+		}
+		assert.Equal(t, expectedCategories, config.Categories)
+	})
+
+	t.Run("should parse support target", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("support:\n  - identifier: platform\n    href: https://platform.cloudogu.com\n    target: newWindow\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		config, err := readWarpConfigFromFile(path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "newWindow", config.Support[0].Target)
+	})
+
+	t.Run("fail because of unknown support target", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("support:\n  - identifier: platform\n    href: https://platform.cloudogu.com\n    target: popup\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		_, err = readWarpConfigFromFile(path)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown target \"popup\"")
+	})
+
+	t.Run("fail because of unknown transform target", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("transforms:\n  - match:\n      source: dogus\n    target: popup\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		_, err = readWarpConfigFromFile(path)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid transform rule 0: unknown target \"popup\"")
+	})
+
+	t.Run("should parse transform rules", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("transforms:\n  - match:\n      source: dogus\n      name: ^Jenkins$\n    category: Development Apps/CI\n    target: newWindow\n    order: 10\n  - match:\n      tag: internal\n    hide: true\n"), 0600)
+		require.NoError(t, err)
+
+		// when
+		config, err := readWarpConfigFromFile(path)
+
+		// then
+		require.NoError(t, err)
+		order := 10
+		expectedTransforms := []TransformRule{
+			{Match: TransformMatch{Source: "dogus", Name: "^Jenkins$"}, Category: "Development Apps/CI", Target: "newWindow", Order: &order},
+			{Match: TransformMatch{Tag: "internal"}, Hide: true},
+		}
+		assert.Equal(t, expectedTransforms, config.Transforms)
 	})
 
 	t.Run("config does not exists", func(t *testing.T) {
@@ -140,3 +208,82 @@ func Test_readWarpConfigFromFile(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestReadDebounceWindow(t *testing.T) {
+	t.Run("should use default", func(t *testing.T) {
+		// when
+		window, err := ReadDebounceWindow()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, DefaultDebounceWindow, window)
+	})
+
+	t.Run("should read duration from environment", func(t *testing.T) {
+		// given
+		t.Setenv("WARP_DEBOUNCE_WINDOW", "500ms")
+
+		// when
+		window, err := ReadDebounceWindow()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 500*time.Millisecond, window)
+	})
+
+	t.Run("should fail on invalid duration", func(t *testing.T) {
+		// given
+		t.Setenv("WARP_DEBOUNCE_WINDOW", "soon")
+
+		// when
+		_, err := ReadDebounceWindow()
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse duration from environment variable [WARP_DEBOUNCE_WINDOW]")
+	})
+}
+
+func TestReadMenuBindAddress(t *testing.T) {
+	t.Run("should use default", func(t *testing.T) {
+		// when
+		address := ReadMenuBindAddress()
+
+		// then
+		assert.Equal(t, "127.0.0.1:8084", address)
+	})
+
+	t.Run("should read address from environment", func(t *testing.T) {
+		// given
+		t.Setenv("WARP_MENU_BIND_ADDRESS", "0")
+
+		// when
+		address := ReadMenuBindAddress()
+
+		// then
+		assert.Equal(t, MenuBindAddressDisabled, address)
+	})
+}
+
+func TestReadDebounceMaxDelay(t *testing.T) {
+	t.Run("should use default", func(t *testing.T) {
+		// when
+		maxDelay, err := ReadDebounceMaxDelay()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, DefaultDebounceMaxDelay, maxDelay)
+	})
+
+	t.Run("should fail on negative duration", func(t *testing.T) {
+		// given
+		t.Setenv("WARP_DEBOUNCE_MAX_DELAY", "-1s")
+
+		// when
+		_, err := ReadDebounceMaxDelay()
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must not be negative")
+	})
+}
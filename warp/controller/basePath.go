@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// GlobalWarpBasePathConfigurationKey contains the public path under which the Cloudogu EcoSystem is served behind a
+// reverse proxy, e.g. "/ces". It is prepended to the hrefs of all entries with the target "self".
+const GlobalWarpBasePathConfigurationKey = "warpmenu_base_path"
+
+var basePathPattern = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)*/?$`)
+
+// readBasePath returns the configured base path without trailing slash. An empty string is returned if no base path
+// is configured or if it is invalid.
+func readBasePath(globalConfig libconfig.GlobalConfig) string {
+	value, exists := globalConfig.Get(libconfig.Key(GlobalWarpBasePathConfigurationKey))
+	if !exists {
+		return ""
+	}
+
+	basePath := strings.TrimSpace(value.String())
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	if !basePathPattern.MatchString(basePath) {
+		ctrl.Log.Info(fmt.Sprintf("Invalid warp menu base path %q in global config key %s, using /", basePath, GlobalWarpBasePathConfigurationKey))
+		return ""
+	}
+	return strings.TrimSuffix(basePath, "/")
+}
+
+// applyBasePath prepends the base path to the absolute paths of all entries with the target "self". Hrefs with a
+// scheme or host are not changed.
+func applyBasePath(categories types2.Categories, basePath string) {
+	if basePath == "" {
+		return
+	}
+
+	for _, category := range categories {
+		for i, entry := range category.Entries {
+			if entry.Target == types2.TARGET_SELF && strings.HasPrefix(entry.Href, "/") && !strings.HasPrefix(entry.Href, "//") {
+				category.Entries[i].Href = basePath + entry.Href
+			}
+		}
+		applyBasePath(category.Children, basePath)
+	}
+}
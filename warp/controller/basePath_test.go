@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readBasePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "should remove trailing slash", value: "/ces/", want: "/ces"},
+		{name: "should add leading slash", value: "ces/tools", want: "/ces/tools"},
+		{name: "should ignore root path", value: "/", want: ""},
+		{name: "should ignore url", value: "https://proxy.example.com/ces", want: ""},
+		{name: "should ignore path with query", value: "/ces?x=1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"warpmenu_base_path": registryconfig.Value(tt.value)})
+
+			// when
+			basePath := readBasePath(globalConfig)
+
+			// then
+			assert.Equal(t, tt.want, basePath)
+		})
+	}
+
+	t.Run("should return empty base path if not configured", func(t *testing.T) {
+		assert.Empty(t, readBasePath(registryconfig.CreateGlobalConfig(registryconfig.Entries{})))
+	})
+}
+
+func Test_applyBasePath(t *testing.T) {
+	// given
+	categories := types2.Categories{{
+		Title: "Development Apps",
+		Entries: types2.Entries{
+			{DisplayName: "Redmine", Href: "/redmine", Target: types2.TARGET_SELF},
+			{DisplayName: "Docs", Href: "https://docs.cloudogu.com", Target: types2.TARGET_SELF},
+			{DisplayName: "CDN", Href: "//cdn.example.com", Target: types2.TARGET_SELF},
+			{DisplayName: "Jenkins", Href: "/jenkins", Target: types2.TARGET_NEW_WINDOW},
+		},
+		Children: types2.Categories{{Title: "CI", Entries: types2.Entries{{DisplayName: "SonarQube", Href: "/sonar", Target: types2.TARGET_SELF}}}},
+	}}
+
+	// when
+	applyBasePath(categories, "/ces")
+
+	// then
+	assert.Equal(t, "/ces/redmine", categories[0].Entries[0].Href)
+	assert.Equal(t, "https://docs.cloudogu.com", categories[0].Entries[1].Href)
+	assert.Equal(t, "//cdn.example.com", categories[0].Entries[2].Href)
+	assert.Equal(t, "/jenkins", categories[0].Entries[3].Href)
+	assert.Equal(t, "/ces/sonar", categories[0].Children[0].Entries[0].Href)
+}
+
+func TestConfigReader_Read_basePath(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"warpmenu_base_path": "/ces/"})
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+	configuration := &config.Configuration{
+		Support: []config.SupportSource{{Identifier: "aboutCloudoguToken", Href: "/info/about"}},
+		Hosts:   []string{"partner.example.com"},
+	}
+	reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(), nil)
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "/ces/info/about", categories[0].Entries[0].Href)
+	assert.Equal(t, "/ces/info/about", reader.HostCategories()["partner.example.com"][0].Entries[0].Href)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+)
+
+// CachedGlobalConfigRepository keeps the global config received from its registry watch, so reading it needs no
+// request to the API server in steady state. The cached config is updated before a change is forwarded to the watcher,
+// so a generation triggered by the watch always reads the changed config. Without a running watch the global config is
+// read from the registry.
+type CachedGlobalConfigRepository struct {
+	GlobalConfigRepository
+	mutex        sync.Mutex
+	globalConfig *libconfig.GlobalConfig
+}
+
+func NewCachedGlobalConfigRepository(repository GlobalConfigRepository) *CachedGlobalConfigRepository {
+	return &CachedGlobalConfigRepository{GlobalConfigRepository: repository}
+}
+
+// Get returns the cached global config or reads it from the registry if no watch is running.
+func (r *CachedGlobalConfigRepository) Get(ctx context.Context) (libconfig.GlobalConfig, error) {
+	r.mutex.Lock()
+	cached := r.globalConfig
+	r.mutex.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	return r.GlobalConfigRepository.Get(ctx)
+}
+
+// Watch watches the global config in the registry and keeps the cache up to date with every change. Only changes
+// matching one of the filters are forwarded, like the registry does. The cache is dropped when the watch ends, because
+// changes are missed until the watch is started again.
+func (r *CachedGlobalConfigRepository) Watch(ctx context.Context, filters ...libconfig.WatchFilter) (<-chan repository.GlobalConfigWatchResult, error) {
+	results, err := r.GlobalConfigRepository.Watch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	globalConfig, err := r.GlobalConfigRepository.Get(ctx)
+	if err != nil {
+		// the registry closes the results when the context ends
+		go func() {
+			for range results {
+			}
+		}()
+		return nil, fmt.Errorf("failed to read global config for cache: %w", err)
+	}
+	r.setCached(&globalConfig)
+
+	forwarded := make(chan repository.GlobalConfigWatchResult)
+	go func() {
+		defer close(forwarded)
+		defer r.setCached(nil)
+		for result := range results {
+			if result.Err == nil {
+				r.setCached(&result.NewState)
+				if !matchesAnyFilter(result, filters) {
+					continue
+				}
+			}
+
+			// the results are drained until the registry closes them, so its watch does not block
+			select {
+			case forwarded <- result:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return forwarded, nil
+}
+
+func (r *CachedGlobalConfigRepository) setCached(globalConfig *libconfig.GlobalConfig) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.globalConfig = globalConfig
+}
+
+func matchesAnyFilter(result repository.GlobalConfigWatchResult, filters []libconfig.WatchFilter) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	diff := result.PrevState.Diff(result.NewState.Config)
+	for _, filter := range filters {
+		if filter(diff) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedGlobalConfigRepository_Get(t *testing.T) {
+	t.Run("should read global config from registry without watch", func(t *testing.T) {
+		// given
+		expected := registryconfig.CreateGlobalConfig(registryconfig.Entries{"fqdn": "ces.example.com"})
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(expected, nil).Twice()
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+
+		// when
+		_, err := cachedRepo.Get(testCtx)
+		require.NoError(t, err)
+		globalConfig, err := cachedRepo.Get(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, expected, globalConfig)
+	})
+
+	t.Run("should return error of registry", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+
+		// when
+		_, err := cachedRepo.Get(testCtx)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestCachedGlobalConfigRepository_Watch(t *testing.T) {
+	initialConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"fqdn": "ces.example.com", "admin_group": "admins"})
+	fqdnChanged := registryconfig.CreateGlobalConfig(registryconfig.Entries{"fqdn": "ces2.example.com", "admin_group": "admins"})
+	adminGroupChanged := registryconfig.CreateGlobalConfig(registryconfig.Entries{"fqdn": "ces2.example.com", "admin_group": "others"})
+	fqdnFilter := registryconfig.KeyFilter("fqdn")
+
+	t.Run("should read global config from cache while watching", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		results := make(chan repository.GlobalConfigWatchResult)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(ctx).Return(results, nil)
+		globalConfigRepoMock.EXPECT().Get(ctx).Return(initialConfig, nil).Once()
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+
+		// when
+		_, err := cachedRepo.Watch(ctx, fqdnFilter)
+
+		// then
+		require.NoError(t, err)
+		globalConfig, err := cachedRepo.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, initialConfig, globalConfig)
+	})
+
+	t.Run("should update cache before forwarding matching changes", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		results := make(chan repository.GlobalConfigWatchResult)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(ctx).Return(results, nil)
+		globalConfigRepoMock.EXPECT().Get(ctx).Return(initialConfig, nil).Once()
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+		forwarded, err := cachedRepo.Watch(ctx, fqdnFilter)
+		require.NoError(t, err)
+
+		// when
+		results <- repository.GlobalConfigWatchResult{PrevState: initialConfig, NewState: fqdnChanged}
+		result := <-forwarded
+
+		// then
+		assert.Equal(t, fqdnChanged, result.NewState)
+		globalConfig, err := cachedRepo.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, fqdnChanged, globalConfig)
+	})
+
+	t.Run("should update cache without forwarding other changes", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		results := make(chan repository.GlobalConfigWatchResult)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(ctx).Return(results, nil)
+		globalConfigRepoMock.EXPECT().Get(ctx).Return(fqdnChanged, nil).Once()
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+		forwarded, err := cachedRepo.Watch(ctx, fqdnFilter)
+		require.NoError(t, err)
+
+		// when
+		results <- repository.GlobalConfigWatchResult{PrevState: fqdnChanged, NewState: adminGroupChanged}
+		results <- repository.GlobalConfigWatchResult{Err: assert.AnError}
+		result := <-forwarded
+
+		// then
+		require.ErrorIs(t, result.Err, assert.AnError)
+		globalConfig, err := cachedRepo.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, adminGroupChanged, globalConfig)
+	})
+
+	t.Run("should read global config from registry after watch ended", func(t *testing.T) {
+		// given
+		results := make(chan repository.GlobalConfigWatchResult)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(testCtx).Return(results, nil)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(initialConfig, nil).Once()
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(fqdnChanged, nil).Once()
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+		forwarded, err := cachedRepo.Watch(testCtx)
+		require.NoError(t, err)
+
+		// when
+		close(results)
+		_, open := <-forwarded
+
+		// then
+		assert.False(t, open)
+		globalConfig, err := cachedRepo.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, fqdnChanged, globalConfig)
+	})
+
+	t.Run("should fail if watch cannot be started", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(testCtx).Return(nil, assert.AnError)
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+
+		// when
+		_, err := cachedRepo.Watch(testCtx)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should fail if global config cannot be read", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(testCtx).Return(make(chan repository.GlobalConfigWatchResult), nil)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		cachedRepo := NewCachedGlobalConfigRepository(globalConfigRepoMock)
+
+		// when
+		_, err := cachedRepo.Watch(testCtx)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
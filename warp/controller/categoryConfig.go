@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+)
+
+// GlobalWarpCategoriesConfigurationKey contains a JSON object with category metadata overriding the warp config,
+// e.g. {"Development Apps": {"collapsed": true}}.
+const GlobalWarpCategoriesConfigurationKey = "warpmenu_categories"
+
+// categoryOverride contains category metadata from the global config. Only values that are set override the
+// metadata from the warp config.
+type categoryOverride struct {
+	Icon        *string `json:"icon"`
+	Description *string `json:"description"`
+	Collapsed   *bool   `json:"collapsed"`
+	Hidden      *bool   `json:"hidden"`
+}
+
+func (reader *ConfigReader) readCategoryOverrides(globalConfig libconfig.GlobalConfig) (map[string]categoryOverride, error) {
+	entry, exists := globalConfig.Get(libconfig.Key(GlobalWarpCategoriesConfigurationKey))
+	if !exists || !ContainsChars(entry.String()) {
+		return map[string]categoryOverride{}, nil
+	}
+
+	overrides := map[string]categoryOverride{}
+	err := json.Unmarshal([]byte(entry.String()), &overrides)
+	if err != nil {
+		return map[string]categoryOverride{}, fmt.Errorf("failed to unmarshal global config key to category overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// applyCategoryMetadata sets the configured metadata on all categories and removes hidden categories.
+func applyCategoryMetadata(categories types2.Categories, configured map[string]config.CategoryConfig, overrides map[string]categoryOverride) types2.Categories {
+	return applyCategoryMetadataWithPath(categories, "", configured, overrides)
+}
+
+func applyCategoryMetadataWithPath(categories types2.Categories, parentPath string, configured map[string]config.CategoryConfig, overrides map[string]categoryOverride) types2.Categories {
+	var result types2.Categories
+	for _, category := range categories {
+		path := category.Title
+		if parentPath != "" {
+			path = parentPath + types2.CategoryPathSeparator + category.Title
+		}
+
+		metadata := mergeCategoryMetadata(configured[path], overrides[path])
+		if metadata.Hidden {
+			continue
+		}
+
+		category.Icon = metadata.Icon
+		category.Description = metadata.Description
+		category.Collapsed = metadata.Collapsed
+		category.Children = applyCategoryMetadataWithPath(category.Children, path, configured, overrides)
+		result = append(result, category)
+	}
+	return result
+}
+
+func mergeCategoryMetadata(configured config.CategoryConfig, override categoryOverride) config.CategoryConfig {
+	if override.Icon != nil {
+		configured.Icon = *override.Icon
+	}
+	if override.Description != nil {
+		configured.Description = *override.Description
+	}
+	if override.Collapsed != nil {
+		configured.Collapsed = *override.Collapsed
+	}
+	if override.Hidden != nil {
+		configured.Hidden = *override.Hidden
+	}
+	return configured
+}
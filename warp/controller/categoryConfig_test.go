@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReader_readCategoryOverrides(t *testing.T) {
+	t.Run("should successfully read overrides", func(t *testing.T) {
+		// given
+		globalConfig := registryconfig.GlobalConfig{
+			Config: registryconfig.CreateConfig(registryconfig.Entries{
+				GlobalWarpCategoriesConfigurationKey: `{"Development Apps": {"collapsed": true, "icon": "code"}}`,
+			}),
+		}
+		reader := &ConfigReader{}
+
+		// when
+		overrides, err := reader.readCategoryOverrides(globalConfig)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, overrides, "Development Apps")
+		assert.True(t, *overrides["Development Apps"].Collapsed)
+		assert.Equal(t, "code", *overrides["Development Apps"].Icon)
+		assert.Nil(t, overrides["Development Apps"].Description)
+		assert.Nil(t, overrides["Development Apps"].Hidden)
+	})
+
+	t.Run("should return no overrides if key does not exist", func(t *testing.T) {
+		// given
+		globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{})
+		reader := &ConfigReader{}
+
+		// when
+		overrides, err := reader.readCategoryOverrides(globalConfig)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, overrides)
+	})
+
+	t.Run("should fail unmarshalling", func(t *testing.T) {
+		// given
+		globalConfig := registryconfig.GlobalConfig{
+			Config: registryconfig.CreateConfig(registryconfig.Entries{
+				GlobalWarpCategoriesConfigurationKey: "not a json object",
+			}),
+		}
+		reader := &ConfigReader{}
+
+		// when
+		_, err := reader.readCategoryOverrides(globalConfig)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to unmarshal global config key to category overrides")
+	})
+}
+
+func Test_applyCategoryMetadata(t *testing.T) {
+	t.Run("should set configured metadata", func(t *testing.T) {
+		// given
+		categories := types2.Categories{
+			{Title: "Development Apps", Children: types2.Categories{{Title: "CI"}}},
+			{Title: "Support"},
+		}
+		configured := map[string]config.CategoryConfig{
+			"Development Apps":    {Icon: "code", Description: "Development"},
+			"Development Apps/CI": {Collapsed: true},
+		}
+
+		// when
+		actual := applyCategoryMetadata(categories, configured, map[string]categoryOverride{})
+
+		// then
+		expected := types2.Categories{
+			{Title: "Development Apps", Icon: "code", Description: "Development", Children: types2.Categories{{Title: "CI", Collapsed: true}}},
+			{Title: "Support"},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should prefer overrides from global config", func(t *testing.T) {
+		// given
+		categories := types2.Categories{{Title: "Development Apps"}}
+		configured := map[string]config.CategoryConfig{
+			"Development Apps": {Icon: "code", Description: "Development", Collapsed: true},
+		}
+		collapsed := false
+		icon := "tools"
+		overrides := map[string]categoryOverride{"Development Apps": {Collapsed: &collapsed, Icon: &icon}}
+
+		// when
+		actual := applyCategoryMetadata(categories, configured, overrides)
+
+		// then
+		expected := types2.Categories{{Title: "Development Apps", Icon: "tools", Description: "Development"}}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should remove hidden categories", func(t *testing.T) {
+		// given
+		categories := types2.Categories{
+			{Title: "Development Apps", Children: types2.Categories{{Title: "CI"}, {Title: "Wiki"}}},
+			{Title: "Support"},
+		}
+		configured := map[string]config.CategoryConfig{"Development Apps/CI": {Hidden: true}}
+		hidden := true
+		overrides := map[string]categoryOverride{"Support": {Hidden: &hidden}}
+
+		// when
+		actual := applyCategoryMetadata(categories, configured, overrides)
+
+		// then
+		expected := types2.Categories{{Title: "Development Apps", Children: types2.Categories{{Title: "Wiki"}}}}
+		assert.Equal(t, expected, actual)
+	})
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/andybalholm/brotli"
+	"github.com/cloudogu/warp-assets/config"
+)
+
+const (
+	gzipFileSuffix   = ".gz"
+	brotliFileSuffix = ".br"
+)
+
+// writeCompressedFile writes the file together with pre-compressed copies, so nginx can serve them with gzip_static.
+// The copies are only compressed again if the content of the file changed. They get the modification time of the
+// file, because nginx and proxies use it for caching.
+func (r *WarpMenuConfigReconciler) writeCompressedFile(name string, data []byte, compression config.CompressionConfig) error {
+	path := filepath.Join(r.warpMenuPath, name)
+	if !compression.Brotli {
+		if err := os.Remove(path + brotliFileSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove brotli compressed copy of %s: %w", path, err)
+		}
+	}
+
+	suffixes := []string{gzipFileSuffix}
+	if compression.Brotli {
+		suffixes = append(suffixes, brotliFileSuffix)
+	}
+	if isFileUnchanged(path, data, suffixes) {
+		return nil
+	}
+
+	// the copies are written first, so a client never gets an older compressed copy than the file
+	for _, suffix := range suffixes {
+		compressed, err := compress(data, suffix)
+		if err != nil {
+			return fmt.Errorf("failed to compress %s: %w", path, err)
+		}
+		if err = r.writeFile(name+suffix, compressed); err != nil {
+			return err
+		}
+	}
+	if err := r.writeFile(name, data); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read modification time of %s: %w", path, err)
+	}
+	for _, suffix := range suffixes {
+		if err = os.Chtimes(path+suffix, info.ModTime(), info.ModTime()); err != nil {
+			return fmt.Errorf("failed to set modification time of %s: %w", path+suffix, err)
+		}
+	}
+	return nil
+}
+
+// removeCompressedFile removes the file together with its pre-compressed copies.
+func removeCompressedFile(path string) error {
+	var errs []error
+	for _, filePath := range []string{path, path + gzipFileSuffix, path + brotliFileSuffix} {
+		if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isFileUnchanged(path string, data []byte, suffixes []string) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(existing, data) {
+		return false
+	}
+	for _, suffix := range suffixes {
+		if _, err = os.Stat(path + suffix); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func compress(data []byte, suffix string) ([]byte, error) {
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	if suffix == brotliFileSuffix {
+		writer = brotli.NewWriterLevel(&buffer, brotli.BestCompression)
+	} else {
+		gzipWriter, err := gzip.NewWriterLevel(&buffer, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		writer = gzipWriter
+	}
+
+	_, err := writer.Write(data)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
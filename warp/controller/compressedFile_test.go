@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarpMenuConfigReconciler_writeCompressedFile(t *testing.T) {
+	t.Run("should write gzip and brotli copies with the modification time of the file", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+
+		// when
+		err := reconciler.writeCompressedFile("menu.json", []byte(`[{"Title":"Links"}]`), config.CompressionConfig{Brotli: true})
+
+		// then
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+
+		gzipData, err := os.ReadFile(path + ".gz")
+		require.NoError(t, err)
+		gzipReader, err := gzip.NewReader(bytes.NewReader(gzipData))
+		require.NoError(t, err)
+		content, err := io.ReadAll(gzipReader)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"Title":"Links"}]`, string(content))
+		gzipInfo, err := os.Stat(path + ".gz")
+		require.NoError(t, err)
+		assert.Equal(t, info.ModTime(), gzipInfo.ModTime())
+
+		brotliData, err := os.ReadFile(path + ".br")
+		require.NoError(t, err)
+		content, err = io.ReadAll(brotli.NewReader(bytes.NewReader(brotliData)))
+		require.NoError(t, err)
+		assert.Equal(t, `[{"Title":"Links"}]`, string(content))
+		brotliInfo, err := os.Stat(path + ".br")
+		require.NoError(t, err)
+		assert.Equal(t, info.ModTime(), brotliInfo.ModTime())
+	})
+
+	t.Run("should not compress unchanged content again", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+		require.NoError(t, reconciler.writeCompressedFile("menu.json", []byte("[]"), config.CompressionConfig{}))
+		oldTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(path+".gz", oldTime, oldTime))
+
+		// when
+		err := reconciler.writeCompressedFile("menu.json", []byte("[]"), config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		info, err := os.Stat(path + ".gz")
+		require.NoError(t, err)
+		assert.Equal(t, oldTime, info.ModTime())
+	})
+
+	t.Run("should compress changed content and remove brotli copy if disabled", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+		require.NoError(t, reconciler.writeCompressedFile("menu.json", []byte("[]"), config.CompressionConfig{Brotli: true}))
+
+		// when
+		err := reconciler.writeCompressedFile("menu.json", []byte(`[{"Title":"Links"}]`), config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		assert.NoFileExists(t, path+".br")
+		gzipData, err := os.ReadFile(path + ".gz")
+		require.NoError(t, err)
+		gzipReader, err := gzip.NewReader(bytes.NewReader(gzipData))
+		require.NoError(t, err)
+		content, err := io.ReadAll(gzipReader)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"Title":"Links"}]`, string(content))
+	})
+}
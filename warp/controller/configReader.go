@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	libconfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/cloudogu/warp-assets/config"
@@ -18,163 +20,352 @@ import (
 
 // ConfigReader reads the configuration for the warp menu from the global configuration
 type ConfigReader struct {
-	configuration       *config.Configuration
-	globalConfigRepo    GlobalConfigRepository
-	doguVersionRegistry DoguVersionRegistry
-	localDoguRepo       LocalDoguRepo
-	doguConverter       DoguConverter
-	externalConverter   ExternalConverter
+	configuration    *config.Configuration
+	globalConfigRepo GlobalConfigRepository
+	sourceReaders    *SourceReaderRegistry
+	rejectedEntries  []RejectedEntry
+	entryPosition    int
+	entryCounts      map[string]int
+	sourceCache      *SourceCache
+	staleSources     []StaleSource
+	transforms       []entryTransform
+	hostCategories   map[string]types2.Categories
+	templateErrors   []TemplateError
+	now              time.Time
+	nextEntryChange  time.Time
 }
 
 const GlobalBlockWarpSupportCategoryConfigurationKey = "block_warpmenu_support_category"
 const GlobalDisabledWarpSupportEntriesConfigurationKey = "disabled_warpmenu_support_entries"
 const GlobalAllowedWarpSupportEntriesConfigurationKey = "allowed_warpmenu_support_entries"
 
+// supportEntryConfigSourceType is the type of sources that are not read, because the support entries are read on
+// every generation.
+const supportEntryConfigSourceType = "support_entry_config"
+
 func NewConfigReader(
 	warpMenuConfiguration *config.Configuration,
 	globalConfigRepo GlobalConfigRepository,
-	doguVersionRegistry DoguVersionRegistry,
-	localDoguRepo LocalDoguRepo,
+	sourceReaders *SourceReaderRegistry,
+	sourceCache *SourceCache,
 ) *ConfigReader {
 	return &ConfigReader{
-		configuration:       warpMenuConfiguration,
-		globalConfigRepo:    globalConfigRepo,
-		doguVersionRegistry: doguVersionRegistry,
-		localDoguRepo:       localDoguRepo,
-		doguConverter:       &types2.DoguConverter{},
-		externalConverter:   &types2.ExternalConverter{},
+		configuration:    warpMenuConfiguration,
+		globalConfigRepo: globalConfigRepo,
+		sourceReaders:    sourceReaders,
+		sourceCache:      sourceCache,
 	}
 }
 
-// Read reads sources specified in a configuration and build warp menu categories for them.
+// Read reads sources specified in a configuration and build warp menu categories for them. The categories of the
+// configured hosts are available with HostCategories.
 func (reader *ConfigReader) Read(ctx context.Context, configuration *config.Configuration) (types2.Categories, error) {
-	var data types2.Categories
+	reader.rejectedEntries = nil
+	reader.entryCounts = map[string]int{}
+	reader.staleSources = nil
+	reader.hostCategories = map[string]types2.Categories{}
+	reader.templateErrors = nil
+	reader.transforms = newEntryTransforms(configuration.Transforms)
+	reader.now = time.Now()
+	reader.nextEntryChange = time.Time{}
+
+	// all sources and the support logic use the same snapshot of the global config
+	globalConfig, globalConfigErr := reader.getGlobalConfig(ctx)
+	if globalConfigErr != nil {
+		ctrl.Log.Info(fmt.Sprintf("Error during Read: %s", globalConfigErr.Error()))
+	}
+	templateVariables := readTemplateVariables(globalConfig, templateVariableKeys(configuration))
 
-	for _, source := range configuration.Sources {
+	parts := menuParts{
+		sourceEntries: make([][]types2.EntryWithCategory, len(configuration.Sources)),
+		basePath:      readBasePath(globalConfig),
+	}
+	var err error
+	parts.mergePolicy, err = types2.NewMergePolicy(configuration.Merge.Key, configuration.Merge.Prefer)
+	if err != nil {
+		ctrl.Log.Info(fmt.Sprintf("Invalid merge configuration, using default: %s", err.Error()))
+	}
+
+	results := reader.readSources(ctx, configuration.Sources, globalConfig, globalConfigErr)
+	for i, source := range configuration.Sources {
 		// Disabled support entries refresh every time
-		if source.Type == "support_entry_config" {
+		if source.Type == supportEntryConfigSourceType {
 			continue
 		}
 
-		categories, err := reader.readSource(ctx, source)
+		entries, err := results[i].entries, results[i].err
+		reader.rejectedEntries = append(reader.rejectedEntries, results[i].rejectedEntries...)
 		if err != nil {
 			ctrl.Log.Info(fmt.Sprintf("Error during Read: %s", err.Error()))
+			if cachedEntries, ok := reader.loadCachedSource(source, err); ok {
+				entries = cachedEntries
+			}
+		} else if reader.sourceCache != nil {
+			reader.sourceCache.Store(sourceKey(source), entries, time.Now())
 		}
-		data.InsertCategories(categories)
+		// the entry counts are the baseline of the shrink guard. They are counted before entries expire or are hidden
+		// by transform rules, so only a failing source shrinks them. Only identical sources share a key and add up.
+		reader.entryCounts[sourceKey(source)] += len(entries)
+		// cached entries are filtered like read entries, so they expire and appear while their source is unavailable
+		entries, nextEntryChange := filterValidEntries(entries, reader.now)
+		reader.nextEntryChange = earliestTime(reader.nextEntryChange, nextEntryChange)
+		parts.sourceEntries[i] = reader.renderEntries(source, entries, templateVariables)
 	}
 
 	ctrl.Log.Info("Read SupportEntries")
 
 	readKeyErrorFmt := "Warning, could not read Key: %v. Err: %v"
 
-	isSupportCategoryBlocked, err := reader.readBool(ctx, GlobalBlockWarpSupportCategoryConfigurationKey)
+	parts.supportCategoryBlocked, err = reader.readBool(globalConfig, GlobalBlockWarpSupportCategoryConfigurationKey)
 	if err != nil {
 		ctrl.Log.Info(fmt.Sprintf(readKeyErrorFmt, GlobalBlockWarpSupportCategoryConfigurationKey, err))
 	}
 
-	disabledSupportEntries, err := reader.readStrings(ctx, GlobalDisabledWarpSupportEntriesConfigurationKey)
+	parts.disabledSupportEntries, err = reader.readStrings(globalConfig, GlobalDisabledWarpSupportEntriesConfigurationKey)
 	if err != nil {
 		ctrl.Log.Info(fmt.Sprintf(readKeyErrorFmt, GlobalDisabledWarpSupportEntriesConfigurationKey, err))
 	}
 
-	allowedSupportEntries, err := reader.readStrings(ctx, GlobalAllowedWarpSupportEntriesConfigurationKey)
+	parts.allowedSupportEntries, err = reader.readStrings(globalConfig, GlobalAllowedWarpSupportEntriesConfigurationKey)
 	if err != nil {
 		ctrl.Log.Info(fmt.Sprintf(readKeyErrorFmt, GlobalAllowedWarpSupportEntriesConfigurationKey, err))
 	}
 
-	supportCategory := reader.readSupport(configuration.Support, isSupportCategoryBlocked, disabledSupportEntries, allowedSupportEntries)
-	data.InsertCategories(supportCategory)
-	return data, nil
-}
+	supportSources, nextSupportChange := filterValidSupportSources(configuration.Support, reader.now)
+	reader.nextEntryChange = earliestTime(reader.nextEntryChange, nextSupportChange)
+	parts.supportSources = reader.renderSupportSources(supportSources, templateVariables)
 
-func (reader *ConfigReader) readSource(ctx context.Context, source config.Source) (types2.Categories, error) {
-	switch source.Type {
-	case "dogus":
-		return reader.dogusReader(ctx, source)
-	case "externals":
-		return reader.externalsReader(ctx, source)
+	parts.sorting, err = types2.NewSorting(configuration.Sorting.Locale, configuration.Sorting.Mode, configuration.Sorting.Categories)
+	if err != nil {
+		ctrl.Log.Info(fmt.Sprintf("Invalid sorting configuration, using default: %s", err.Error()))
+		parts.sorting = types2.DefaultSorting()
 	}
-	return nil, errors.Errorf("wrong source type: %v", source.Type)
-}
 
-func (reader *ConfigReader) externalsReader(ctx context.Context, source config.Source) (types2.Categories, error) {
-	ctrl.Log.Info(fmt.Sprintf("Read externals from %s for warp menu in global config", source.Path))
-	children, err := reader.readGlobalConfigDir(ctx, removeLegacyGlobalConfigPrefix(source.Path))
+	parts.categoryOverrides, err = reader.readCategoryOverrides(globalConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read root entry %s from config: %w", source.Path, err)
+		ctrl.Log.Info(fmt.Sprintf(readKeyErrorFmt, GlobalWarpCategoriesConfigurationKey, err))
+	}
+
+	for _, host := range validHosts(configuration.Hosts) {
+		reader.hostCategories[host] = reader.buildMenu(configuration, parts, host)
+	}
+	return reader.buildMenu(configuration, parts, defaultHost), nil
+}
+
+// menuParts contains everything read for a warp menu generation. The menus of all hosts are built from the same
+// parts.
+type menuParts struct {
+	// sourceEntries contains the entries by the index of their source.
+	sourceEntries          [][]types2.EntryWithCategory
+	supportSources         []config.SupportSource
+	supportCategoryBlocked bool
+	disabledSupportEntries []string
+	allowedSupportEntries  []string
+	mergePolicy            types2.MergePolicy
+	sorting                types2.Sorting
+	categoryOverrides      map[string]categoryOverride
+	basePath               string
+}
+
+// buildMenu creates the categories of the menu for the host. Transform rules for other hosts are not applied.
+func (reader *ConfigReader) buildMenu(configuration *config.Configuration, parts menuParts, host string) types2.Categories {
+	var data types2.Categories
+	reader.entryPosition = 0
+	for i, source := range configuration.Sources {
+		if source.Type == supportEntryConfigSourceType {
+			continue
+		}
+
+		categories := reader.createCategories(applyEntryTransforms(reader.transforms, host, source.Type, parts.sourceEntries[i]), parts.mergePolicy)
+		reader.numberEntries(categories)
+		data.InsertCategoriesWithPolicy(categories, parts.mergePolicy)
 	}
-	var externals []types2.EntryWithCategory
-	for _, value := range children {
-		external, unmarshalErr := reader.externalConverter.ReadAndUnmarshalExternal(value)
-		if unmarshalErr != nil {
-			ctrl.Log.Error(unmarshalErr, fmt.Sprintf("failed to read and unmarshal external link key %q", value))
+
+	supportCategory := reader.readSupport(parts.supportSources, parts.supportCategoryBlocked, parts.disabledSupportEntries, parts.allowedSupportEntries, parts.mergePolicy)
+	reader.numberEntries(supportCategory)
+	data.InsertCategoriesWithPolicy(supportCategory, parts.mergePolicy)
+
+	applyEntryWeights(data, configuration.Sorting.Weights)
+	parts.sorting.Sort(data)
+
+	applyBasePath(data, parts.basePath)
+	return applyCategoryMetadata(data, configuration.Categories, parts.categoryOverrides)
+}
+
+// sourceResult is the result of reading a single source.
+type sourceResult struct {
+	// entries contains all entries read from the source, including entries that are not valid now. Transform rules are
+	// applied per host later.
+	entries         []types2.EntryWithCategory
+	rejectedEntries []RejectedEntry
+	err             error
+}
+
+// readSources reads all sources concurrently, so a slow source does not delay the others. The results have the order
+// of the sources.
+func (reader *ConfigReader) readSources(ctx context.Context, sources []config.Source, globalConfig libconfig.GlobalConfig, globalConfigErr error) []sourceResult {
+	results := make([]sourceResult, len(sources))
+	var waitGroup sync.WaitGroup
+	for i, source := range sources {
+		if source.Type == supportEntryConfigSourceType {
 			continue
 		}
-		externals = append(externals, external)
+
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			results[i] = reader.readSourceWithTimeout(ctx, source, globalConfig, globalConfigErr)
+		}()
 	}
-	return reader.createCategories(externals), nil
+	waitGroup.Wait()
+	return results
 }
 
-func (reader *ConfigReader) readGlobalConfigDir(ctx context.Context, key string) (map[string]string, error) {
-	globalConfig, err := reader.getGlobalConfig(ctx)
-	if err != nil {
-		return nil, err
+// readSourceWithTimeout reads the source until its timeout expires. A panic while reading the source is returned as
+// error, so it does not affect the other sources.
+func (reader *ConfigReader) readSourceWithTimeout(ctx context.Context, source config.Source, globalConfig libconfig.GlobalConfig, globalConfigErr error) sourceResult {
+	timeout := sourceTimeout(source)
+	sourceCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resultChan := make(chan sourceResult, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				resultChan <- sourceResult{err: fmt.Errorf("panic while reading source %s: %v", sourceKey(source), recovered)}
+			}
+		}()
+		resultChan <- reader.readSource(sourceCtx, source, globalConfig, globalConfigErr)
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result
+	case <-sourceCtx.Done():
+		return sourceResult{err: fmt.Errorf("failed to read source %s within %s: %w", sourceKey(source), timeout, sourceCtx.Err())}
 	}
+}
 
-	entries := globalConfig.GetAll()
-	children := make(map[string]string, len(entries))
-	for entryKey, entryValue := range entries {
-		if strings.HasPrefix(entryKey.String(), key) && entryKey.String() != key {
-			children[entryKey.String()] = entryValue.String()
-		}
+func sourceTimeout(source config.Source) time.Duration {
+	if source.Timeout == "" {
+		return config.DefaultSourceTimeout
 	}
 
-	return children, nil
+	timeout, err := time.ParseDuration(source.Timeout)
+	if err != nil || timeout <= 0 {
+		ctrl.Log.Info(fmt.Sprintf("Invalid timeout %q of source %s, using default %s", source.Timeout, sourceKey(source), config.DefaultSourceTimeout))
+		return config.DefaultSourceTimeout
+	}
+	return timeout
 }
 
-// dogusReader reads from dogu repository and converts the keys and values to a warp menu
-// conform structure
-func (reader *ConfigReader) dogusReader(ctx context.Context, source config.Source) (types2.Categories, error) {
-	ctrl.Log.Info(fmt.Sprintf("Read dogus from %s for warp menu", source.Path))
-	allCurrentDoguVersions, err := reader.doguVersionRegistry.GetCurrentOfAll(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get all current dogu versions: %w", err)
+// readSource reads the source with the reader registered for its type. Sources that read global config keys fail if
+// the global config is not available. Other readers get the error of the global config to decide themselves.
+func (reader *ConfigReader) readSource(ctx context.Context, source config.Source, globalConfig libconfig.GlobalConfig, globalConfigErr error) sourceResult {
+	sourceReader, ok := reader.sourceReaders.Get(source.Type)
+	if !ok {
+		return sourceResult{err: errors.Errorf("wrong source type: %v", source.Type)}
+	}
+
+	if err := validateSource(source, sourceReader.Schema()); err != nil {
+		return sourceResult{err: err}
 	}
 
-	if len(allCurrentDoguVersions) == 0 {
-		return []*types2.Category{}, nil
+	if globalConfigErr != nil && len(sourceReader.WatchTriggers(source).GlobalConfigKeys) > 0 {
+		return sourceResult{err: fmt.Errorf("failed to read root entry %s from config: %w", source.Path, globalConfigErr)}
 	}
 
-	allCurrentDogus, err := reader.localDoguRepo.GetAll(ctx, allCurrentDoguVersions)
+	result, err := sourceReader.Read(ctx, source, globalConfig, globalConfigErr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all dogu specs with current versions: %w", err)
+		return sourceResult{err: err}
 	}
+	return sourceResult{entries: result.Entries, rejectedEntries: result.RejectedEntries}
+}
 
-	var doguCategories []types2.EntryWithCategory
-	for _, currentDogu := range allCurrentDogus {
-		doguCategory, err := reader.doguConverter.CreateEntryWithCategoryFromDogu(currentDogu, source.Tag)
-		if err == nil && doguCategory.Entry.Title != "" {
-			ctrl.Log.Info(fmt.Sprintf("Add dogu %s with category %s", currentDogu.GetSimpleName(), doguCategory.Category))
-			doguCategories = append(doguCategories, doguCategory)
-		}
+// EntryCounts returns the number of entries read from each source during the last Read, keyed by type and path of
+// the source, e.g. "dogus:/dogu".
+func (reader *ConfigReader) EntryCounts() map[string]int {
+	result := make(map[string]int, len(reader.entryCounts))
+	for key, count := range reader.entryCounts {
+		result[key] = count
 	}
+	return result
+}
 
-	return reader.createCategories(doguCategories), nil
+// HostCategories returns the categories of the menus of the configured hosts built during the last Read, keyed by the
+// lowercase host name.
+func (reader *ConfigReader) HostCategories() map[string]types2.Categories {
+	return reader.hostCategories
 }
 
-func (reader *ConfigReader) readStrings(ctx context.Context, registryKey string) ([]string, error) {
-	globalConfig, err := reader.getGlobalConfig(ctx)
-	if err != nil {
-		return nil, err
+// TemplateErrors returns the entries that were skipped during the last Read, because their href or description could
+// not be rendered.
+func (reader *ConfigReader) TemplateErrors() []TemplateError {
+	return append([]TemplateError{}, reader.templateErrors...)
+}
+
+// NextEntryChange returns the next time at which an entry read during the last Read appears or disappears. It is zero
+// if no entry is limited in time.
+func (reader *ConfigReader) NextEntryChange() time.Time {
+	return reader.nextEntryChange
+}
+
+// StaleSources returns the sources that failed during the last Read and were replaced by their last successful
+// result, sorted by source.
+func (reader *ConfigReader) StaleSources() []StaleSource {
+	result := append([]StaleSource{}, reader.staleSources...)
+	sortStaleSources(result)
+	return result
+}
+
+// loadCachedSource returns the last successful result of the failed source and records it as stale.
+func (reader *ConfigReader) loadCachedSource(source config.Source, readErr error) ([]types2.EntryWithCategory, bool) {
+	if reader.sourceCache == nil {
+		return nil, false
 	}
 
+	entries, lastSuccess, ok := reader.sourceCache.Load(sourceKey(source))
+	if !ok {
+		return nil, false
+	}
+
+	ctrl.Log.Info(fmt.Sprintf("Using entries of source %s read at %s", sourceKey(source), lastSuccess.Format(time.RFC3339)))
+	reader.staleSources = append(reader.staleSources, StaleSource{
+		Source:      sourceKey(source),
+		LastSuccess: lastSuccess,
+		Reason:      readErr.Error(),
+	})
+	return entries, true
+}
+
+// sourceKey identifies a source in the cache and the status. Sources that differ only in their tag read different
+// entries, so the tag is part of the key.
+func sourceKey(source config.Source) string {
+	key := source.Type + ":" + source.Path
+	if source.Tag != "" {
+		key += "#" + source.Tag
+	}
+	return key
+}
+
+// RejectedEntries returns the global config keys that were skipped during the last Read, sorted by key.
+func (reader *ConfigReader) RejectedEntries() []RejectedEntry {
+	rejected := make([]RejectedEntry, len(reader.rejectedEntries))
+	copy(rejected, reader.rejectedEntries)
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Key < rejected[j].Key
+	})
+	return rejected
+}
+
+func (reader *ConfigReader) readStrings(globalConfig libconfig.GlobalConfig, registryKey string) ([]string, error) {
 	entry, exists := globalConfig.Get(libconfig.Key(registryKey))
 	if !exists || !ContainsChars(entry.String()) {
 		return []string{}, nil
 	}
 
 	var stringSlice []string
-	err = json.Unmarshal([]byte(entry.String()), &stringSlice)
+	err := json.Unmarshal([]byte(entry.String()), &stringSlice)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to unmarshal global config key to string slice: %w", err)
 	}
@@ -182,15 +373,6 @@ func (reader *ConfigReader) readStrings(ctx context.Context, registryKey string)
 	return stringSlice, nil
 }
 
-func removeLegacyGlobalConfigPrefix(key string) string {
-	if strings.HasPrefix(key, "config/_global") || strings.HasPrefix(key, "/config/_global") {
-		_, after, _ := strings.Cut(key, "config/_global/")
-		return after
-	}
-
-	return key
-}
-
 func (reader *ConfigReader) getGlobalConfig(ctx context.Context) (libconfig.GlobalConfig, error) {
 	globalConfig, err := reader.globalConfigRepo.Get(ctx)
 	if err != nil {
@@ -200,12 +382,7 @@ func (reader *ConfigReader) getGlobalConfig(ctx context.Context) (libconfig.Glob
 	return globalConfig, nil
 }
 
-func (reader *ConfigReader) readBool(ctx context.Context, registryKey string) (bool, error) {
-	globalConfig, err := reader.getGlobalConfig(ctx)
-	if err != nil {
-		return false, err
-	}
-
+func (reader *ConfigReader) readBool(globalConfig libconfig.GlobalConfig, registryKey string) (bool, error) {
 	entry, exists := globalConfig.Get(libconfig.Key(registryKey))
 	if !exists || !ContainsChars(entry.String()) {
 		return false, nil
@@ -219,26 +396,31 @@ func (reader *ConfigReader) readBool(ctx context.Context, registryKey string) (b
 	return boolValue, nil
 }
 
-func (reader *ConfigReader) readSupport(supportSources []config.SupportSource, blocked bool, disabledEntries []string, allowedEntries []string) types2.Categories {
+func (reader *ConfigReader) readSupport(supportSources []config.SupportSource, blocked bool, disabledEntries []string, allowedEntries []string, policy types2.MergePolicy) types2.Categories {
 	var supportEntries []types2.EntryWithCategory
 
 	for _, supportSource := range supportSources {
 		if (blocked && StringInSlice(supportSource.Identifier, allowedEntries)) || (!blocked && !StringInSlice(supportSource.Identifier, disabledEntries)) {
 			// support category is blocked, but this entry is explicitly allowed OR support category is NOT blocked and this entry is NOT explicitly disabled
 
-			entry := types2.Entry{Title: supportSource.Identifier, Href: supportSource.Href, Target: types2.TARGET_SELF}
+			entry := types2.Entry{Title: supportSource.Identifier, Href: supportSource.Href, Target: types2.TARGET_SELF, Weight: supportSource.Order}
 			if supportSource.External {
 				entry.Target = types2.TARGET_EXTERNAL
 			}
+			// the target was validated when the warp config was read
+			if target, err := types2.ParseTarget(supportSource.Target); err == nil {
+				entry.Target = target
+			}
 
 			supportEntries = append(supportEntries, types2.EntryWithCategory{Entry: entry, Category: "Support"})
 		}
 	}
 
-	return reader.createCategories(supportEntries)
+	return reader.createCategories(supportEntries, policy)
 }
 
-func (reader *ConfigReader) createCategories(entries []types2.EntryWithCategory) types2.Categories {
+// createCategories groups the entries by category and resolves duplicate entries with the configured merge policy.
+func (reader *ConfigReader) createCategories(entries []types2.EntryWithCategory, policy types2.MergePolicy) types2.Categories {
 	categories := map[string]*types2.Category{}
 
 	for _, entry := range entries {
@@ -248,7 +430,6 @@ func (reader *ConfigReader) createCategories(entries []types2.EntryWithCategory)
 			category = &types2.Category{
 				Title:   categoryName,
 				Entries: types2.Entries{},
-				Order:   reader.configuration.Order[categoryName],
 			}
 			categories[categoryName] = category
 		}
@@ -257,13 +438,53 @@ func (reader *ConfigReader) createCategories(entries []types2.EntryWithCategory)
 
 	result := types2.Categories{}
 	for _, cat := range categories {
-		sort.Sort(cat.Entries)
-		result = append(result, cat)
+		if reader.configuration.NestedCategories {
+			result.InsertNestedCategoryWithPolicy(cat, policy)
+		} else {
+			result.InsertCategoryWithPolicy(cat, policy)
+		}
 	}
-	sort.Sort(result)
+	reader.applyCategoryOrder(result, "")
+	result.SortRecursive()
 	return result
 }
 
+// numberEntries sets the position of the entries in the order in which they were read.
+func (reader *ConfigReader) numberEntries(categories types2.Categories) {
+	for _, category := range categories {
+		for i := range category.Entries {
+			reader.entryPosition++
+			category.Entries[i].Position = reader.entryPosition
+		}
+		reader.numberEntries(category.Children)
+	}
+}
+
+// applyEntryWeights sets the configured weight of all entries with a matching id.
+func applyEntryWeights(categories types2.Categories, weights map[string]int) {
+	for _, category := range categories {
+		for i, entry := range category.Entries {
+			if weight, ok := weights[entry.ID]; ok && entry.ID != "" {
+				category.Entries[i].Weight = weight
+			}
+		}
+		applyEntryWeights(category.Children, weights)
+	}
+}
+
+// applyCategoryOrder sets the configured order for every category. Child categories are looked up by their path,
+// e.g. "Development Apps/CI".
+func (reader *ConfigReader) applyCategoryOrder(categories types2.Categories, parentPath string) {
+	for _, category := range categories {
+		path := category.Title
+		if parentPath != "" {
+			path = parentPath + types2.CategoryPathSeparator + category.Title
+		}
+		category.Order = reader.configuration.Order[path]
+		reader.applyCategoryOrder(category.Children, path)
+	}
+}
+
 func StringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
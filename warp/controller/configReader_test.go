@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 var testCtx = context.Background()
@@ -29,7 +30,7 @@ func TestConfigReader_readSupport(t *testing.T) {
 	}
 
 	t.Run("should successfully read support entries without filters", func(t *testing.T) {
-		actual := reader.readSupport(supportSources, false, []string{}, []string{})
+		actual := reader.readSupport(supportSources, false, []string{}, []string{}, types2.DefaultMergePolicy)
 
 		expectedCategories := types2.Categories{
 			{Title: "Support", Entries: []types2.Entry{
@@ -41,14 +42,14 @@ func TestConfigReader_readSupport(t *testing.T) {
 	})
 
 	t.Run("should block all entries", func(t *testing.T) {
-		actual := reader.readSupport(supportSources, true, []string{}, []string{})
+		actual := reader.readSupport(supportSources, true, []string{}, []string{}, types2.DefaultMergePolicy)
 
 		expectedCategories := types2.Categories{}
 		assert.Equal(t, expectedCategories, actual)
 	})
 
 	t.Run("should add allowed entries when blocked", func(t *testing.T) {
-		actual := reader.readSupport(supportSources, true, []string{}, []string{"myCloudogu"})
+		actual := reader.readSupport(supportSources, true, []string{}, []string{"myCloudogu"}, types2.DefaultMergePolicy)
 
 		expectedCategories := types2.Categories{
 			{Title: "Support", Entries: []types2.Entry{
@@ -58,7 +59,7 @@ func TestConfigReader_readSupport(t *testing.T) {
 	})
 
 	t.Run("should remove disabled entries when not blocked", func(t *testing.T) {
-		actual := reader.readSupport(supportSources, false, []string{"aboutCloudoguToken", "docsCloudoguComUrl"}, []string{})
+		actual := reader.readSupport(supportSources, false, []string{"aboutCloudoguToken", "docsCloudoguComUrl"}, []string{}, types2.DefaultMergePolicy)
 
 		expectedCategories := types2.Categories{
 			{Title: "Support", Entries: []types2.Entry{
@@ -68,7 +69,7 @@ func TestConfigReader_readSupport(t *testing.T) {
 	})
 
 	t.Run("should remove disabled entries when not blocked", func(t *testing.T) {
-		actual := reader.readSupport(supportSources, false, []string{"aboutCloudoguToken", "docsCloudoguComUrl"}, []string{})
+		actual := reader.readSupport(supportSources, false, []string{"aboutCloudoguToken", "docsCloudoguComUrl"}, []string{}, types2.DefaultMergePolicy)
 
 		expectedCategories := types2.Categories{
 			{Title: "Support", Entries: []types2.Entry{
@@ -76,57 +77,43 @@ func TestConfigReader_readSupport(t *testing.T) {
 			}}}
 		assert.Equal(t, expectedCategories, actual)
 	})
+
+	t.Run("should use configured target", func(t *testing.T) {
+		sources := []config.SupportSource{{Identifier: "platform", External: true, Href: "https://platform.cloudogu.com", Target: "newWindow"}}
+
+		actual := reader.readSupport(sources, false, []string{}, []string{}, types2.DefaultMergePolicy)
+
+		expectedCategories := types2.Categories{
+			{Title: "Support", Entries: []types2.Entry{
+				{Title: "platform", Target: types2.TARGET_NEW_WINDOW, Href: "https://platform.cloudogu.com"},
+			}}}
+		assert.Equal(t, expectedCategories, actual)
+	})
 }
 func TestConfigReader_readStrings(t *testing.T) {
 	t.Run("should successfully read strings", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
 		globalConfig := registryconfig.GlobalConfig{
 			Config: registryconfig.CreateConfig(registryconfig.Entries{
 				"disabled_warpmenu_support_entries": "[\"lorem\",\"ipsum\"]",
 			}),
 		}
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
-
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
+		reader := &ConfigReader{}
 
-		identifiers, err := reader.readStrings(testCtx, "disabled_warpmenu_support_entries")
+		identifiers, err := reader.readStrings(globalConfig, "disabled_warpmenu_support_entries")
 		require.NoError(t, err)
 		assert.Equal(t, []string{"lorem", "ipsum"}, identifiers)
 	})
 
-	t.Run("should fail getting global config", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
-
-		_, err := reader.readStrings(testCtx, "disabled_warpmenu_support_entries")
-		require.Error(t, err)
-		assert.ErrorIs(t, err, assert.AnError)
-		assert.ErrorContains(t, err, "failed to get global config")
-	})
-
 	t.Run("should fail unmarshalling", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
 		globalConfig := registryconfig.GlobalConfig{
 			Config: registryconfig.CreateConfig(registryconfig.Entries{
 				"disabled_warpmenu_support_entries": "not a string array]",
 			}),
 		}
 
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		reader := &ConfigReader{}
 
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
-
-		identifiers, err := reader.readStrings(testCtx, "disabled_warpmenu_support_entries")
+		identifiers, err := reader.readStrings(globalConfig, "disabled_warpmenu_support_entries")
 		require.Error(t, err)
 		assert.ErrorContains(t, err, "failed to unmarshal global config key to string slice")
 		assert.Equal(t, []string{}, identifiers)
@@ -135,75 +122,43 @@ func TestConfigReader_readStrings(t *testing.T) {
 
 func TestConfigReader_readBool(t *testing.T) {
 	t.Run("should successfully read true bool", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
 		globalConfig := registryconfig.GlobalConfig{
 			Config: registryconfig.CreateConfig(registryconfig.Entries{
 				"myBool": "true",
 			}),
 		}
 
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
-
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
+		reader := &ConfigReader{}
 
-		boolValue, err := reader.readBool(testCtx, "myBool")
+		boolValue, err := reader.readBool(globalConfig, "myBool")
 		require.NoError(t, err)
 		assert.True(t, boolValue)
 	})
 
 	t.Run("should successfully read false bool", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
 		globalConfig := registryconfig.GlobalConfig{
 			Config: registryconfig.CreateConfig(registryconfig.Entries{
 				"myBool": "false",
 			}),
 		}
 
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
-
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
+		reader := &ConfigReader{}
 
-		boolValue, err := reader.readBool(testCtx, "myBool")
+		boolValue, err := reader.readBool(globalConfig, "myBool")
 		require.NoError(t, err)
 		assert.False(t, boolValue)
 	})
 
-	t.Run("should fail getting global config", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
-
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
-
-		_, err := reader.readBool(testCtx, "myBool")
-		require.Error(t, err)
-		assert.ErrorIs(t, err, assert.AnError)
-		assert.ErrorContains(t, err, "failed to get global config")
-	})
-
 	t.Run("should fail unmarshalling", func(t *testing.T) {
-		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
-
 		globalConfig := registryconfig.GlobalConfig{
 			Config: registryconfig.CreateConfig(registryconfig.Entries{
 				"myBool": "not a pool",
 			}),
 		}
 
-		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
-
-		reader := &ConfigReader{
-			globalConfigRepo: mockGlobalConfigRepo,
-		}
+		reader := &ConfigReader{}
 
-		boolValue, err := reader.readBool(testCtx, "myBool")
+		boolValue, err := reader.readBool(globalConfig, "myBool")
 		require.Error(t, err)
 		assert.ErrorContains(t, err, "failed to unmarshal value \"not a pool\" to bool")
 		assert.False(t, boolValue)
@@ -232,10 +187,9 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		mockExternalConverter := NewMockExternalConverter(t)
 		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).Return(cloudoguEntryWithCategory, nil)
 		reader := &ConfigReader{
-			configuration:     &config.Configuration{Support: []config.SupportSource{}},
-			globalConfigRepo:  mockGlobalConfigRepo,
-			doguConverter:     mockDoguConverter,
-			externalConverter: mockExternalConverter,
+			configuration:    &config.Configuration{Support: []config.SupportSource{}},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{doguConverter: mockDoguConverter}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
 		}
 
 		// when
@@ -245,6 +199,7 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		assert.Empty(t, err)
 		assert.NotEmpty(t, actual)
 		assert.Equal(t, 2, len(actual))
+		assert.Equal(t, map[string]int{"externals:/path/to/external/link#tag": 1}, reader.EntryCounts())
 	})
 
 	t.Run("success with one dogu and support link", func(t *testing.T) {
@@ -273,17 +228,14 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		redmineVersion := parseVersion(t, "5.1.3-1")
 		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *redmineVersion}
 		currentDoguVersions := []dogu.SimpleNameVersion{redmineDoguVersion}
-		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(currentDoguVersions, nil)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(mock.Anything).Return(currentDoguVersions, nil)
 		doguSpecRepoMock := NewMockLocalDoguRepo(t)
-		doguSpecRepoMock.EXPECT().GetAll(testCtx, currentDoguVersions).Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t)}, nil)
+		doguSpecRepoMock.EXPECT().GetAll(mock.Anything, currentDoguVersions).Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t)}, nil)
 
 		reader := &ConfigReader{
-			configuration:       &config.Configuration{Support: []config.SupportSource{}},
-			globalConfigRepo:    mockGlobalConfigRepo,
-			doguConverter:       mockDoguConverter,
-			externalConverter:   mockExternalConverter,
-			doguVersionRegistry: versionRegistryMock,
-			localDoguRepo:       doguSpecRepoMock,
+			configuration:    &config.Configuration{Support: []config.SupportSource{}},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{doguConverter: mockDoguConverter, doguVersionRegistry: versionRegistryMock, localDoguRepo: doguSpecRepoMock}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
 		}
 
 		// when
@@ -295,6 +247,35 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		assert.Equal(t, 2, len(actual))
 	})
 
+	t.Run("should read global config once for all sources and support entries", func(t *testing.T) {
+		// given
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		globalConfig := registryconfig.GlobalConfig{
+			Config: registryconfig.CreateConfig(registryconfig.Entries{
+				"externals/cloudogu": "URL: https://www.cloudogu.com",
+				"links/docs":         "URL: https://docs.cloudogu.com",
+				GlobalBlockWarpSupportCategoryConfigurationKey:  "true",
+				GlobalAllowedWarpSupportEntriesConfigurationKey: "[\"supportSrc\"]",
+			}),
+		}
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil).Once()
+		mockExternalConverter := NewMockExternalConverter(t)
+		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).Return(types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Link", Href: "https://www.cloudogu.com"}, Category: "External"}, nil).Twice()
+		reader := &ConfigReader{
+			configuration:    &config.Configuration{},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
+		}
+		sources := []config.Source{{Path: "externals", Type: "externals"}, {Path: "links", Type: "externals"}}
+
+		// when
+		actual, err := reader.Read(testCtx, &config.Configuration{Sources: sources, Support: testSupportSources})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(actual))
+	})
+
 	t.Run("error during external Read should not result in an error", func(t *testing.T) {
 		// given
 		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
@@ -313,10 +294,9 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		mockExternalConverter := NewMockExternalConverter(t)
 		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).Return(types2.EntryWithCategory{}, assert.AnError)
 		reader := &ConfigReader{
-			configuration:     &config.Configuration{Support: []config.SupportSource{}},
-			globalConfigRepo:  mockGlobalConfigRepo,
-			doguConverter:     mockDoguConverter,
-			externalConverter: mockExternalConverter,
+			configuration:    &config.Configuration{Support: []config.SupportSource{}},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{doguConverter: mockDoguConverter}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
 		}
 
 		// when
@@ -326,6 +306,113 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		assert.Empty(t, err)
 		assert.NotEmpty(t, actual)
 		assert.Equal(t, 1, len(actual))
+		expectedRejected := []RejectedEntry{{Key: "/path/to/external/link/Cloudogu", Reason: assert.AnError.Error()}}
+		assert.Equal(t, expectedRejected, reader.RejectedEntries())
+	})
+
+	t.Run("should use cached result of failed source", func(t *testing.T) {
+		// given
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		lastSuccess := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		sourceCache := NewSourceCache()
+		sourceCache.Store("externals:/path/to/external/link#tag", []types2.EntryWithCategory{
+			{Category: "External", Entry: types2.Entry{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com", Target: types2.TARGET_EXTERNAL, ID: "Cloudogu", Source: types2.SourceExternal}},
+		}, lastSuccess)
+		reader := NewConfigReader(&config.Configuration{}, mockGlobalConfigRepo, NewSourceReaderRegistry(NewExternalsSourceReader()), sourceCache)
+
+		// when
+		actual, err := reader.Read(testCtx, &config.Configuration{Sources: testSources})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "External", actual[0].Title)
+		assert.Equal(t, types2.Entries{{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com", Target: types2.TARGET_EXTERNAL, ID: "Cloudogu", Source: types2.SourceExternal, Position: 1}}, actual[0].Entries)
+		require.Len(t, reader.StaleSources(), 1)
+		assert.Equal(t, "externals:/path/to/external/link#tag", reader.StaleSources()[0].Source)
+		assert.Equal(t, lastSuccess, reader.StaleSources()[0].LastSuccess)
+		assert.Contains(t, reader.StaleSources()[0].Reason, assert.AnError.Error())
+		assert.Equal(t, map[string]int{"externals:/path/to/external/link#tag": 1}, reader.EntryCounts())
+	})
+
+	t.Run("should match tags of cached entries in transform rules", func(t *testing.T) {
+		// given
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		sourceCache := NewSourceCache()
+		sourceCache.Store("externals:/path/to/external/link#tag", []types2.EntryWithCategory{
+			{Category: "External", Entry: types2.Entry{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com", Target: types2.TARGET_EXTERNAL}},
+			{Category: "External", Entry: types2.Entry{DisplayName: "Intranet", Href: "https://intranet.example.com", Target: types2.TARGET_EXTERNAL}, Tags: []string{"internal"}},
+		}, time.Now())
+		reader := NewConfigReader(&config.Configuration{}, mockGlobalConfigRepo, NewSourceReaderRegistry(NewExternalsSourceReader()), sourceCache)
+		transforms := []config.TransformRule{{Match: config.TransformMatch{Tag: "internal"}, Hide: true}}
+
+		// when
+		actual, err := reader.Read(testCtx, &config.Configuration{Sources: testSources, Transforms: transforms})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		require.Len(t, actual[0].Entries, 1)
+		assert.Equal(t, "Cloudogu", actual[0].Entries[0].DisplayName)
+	})
+
+	t.Run("should filter cached entries by their validity", func(t *testing.T) {
+		// given
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		now := time.Now().UTC().Truncate(time.Second)
+		sourceCache := NewSourceCache()
+		sourceCache.Store("externals:/path/to/external/link#tag", []types2.EntryWithCategory{
+			{Category: "External", Entry: types2.Entry{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com", Target: types2.TARGET_EXTERNAL}},
+			{Category: "External", Entry: types2.Entry{DisplayName: "Expired", Href: "https://expired.example.com", Target: types2.TARGET_EXTERNAL}, ValidUntil: now.Add(-time.Hour)},
+			{Category: "External", Entry: types2.Entry{DisplayName: "Upcoming", Href: "https://upcoming.example.com", Target: types2.TARGET_EXTERNAL}, ValidFrom: now.Add(time.Hour)},
+		}, now.Add(-2*time.Hour))
+		reader := NewConfigReader(&config.Configuration{}, mockGlobalConfigRepo, NewSourceReaderRegistry(NewExternalsSourceReader()), sourceCache)
+
+		// when
+		actual, err := reader.Read(testCtx, &config.Configuration{Sources: testSources})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		require.Len(t, actual[0].Entries, 1)
+		assert.Equal(t, "Cloudogu", actual[0].Entries[0].DisplayName)
+		assert.Equal(t, now.Add(time.Hour), reader.NextEntryChange())
+		assert.Equal(t, map[string]int{"externals:/path/to/external/link#tag": 3}, reader.EntryCounts())
+	})
+
+	t.Run("should cache result of successful source", func(t *testing.T) {
+		// given
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		globalConfig := registryconfig.GlobalConfig{
+			Config: registryconfig.CreateConfig(registryconfig.Entries{
+				"/path/to/external/link/Cloudogu": "[\"lorem\", \"ipsum\"]",
+			}),
+		}
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		mockExternalConverter := NewMockExternalConverter(t)
+		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).Return(getEntryWithCategory("Cloudogu", "www.cloudogu.com", "Cloudogu", "External", types2.TARGET_EXTERNAL), nil)
+		sourceCache := NewSourceCache()
+		reader := &ConfigReader{
+			configuration:    &config.Configuration{},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceCache:      sourceCache,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
+		}
+
+		// when
+		_, err := reader.Read(testCtx, &config.Configuration{Sources: testSources})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, reader.StaleSources())
+		entries, _, ok := sourceCache.Load("externals:/path/to/external/link#tag")
+		require.True(t, ok)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "External", entries[0].Category)
+		assert.Equal(t, "Cloudogu", entries[0].Entry.ID)
 	})
 
 	t.Run("empty support category should not result in an error", func(t *testing.T) {
@@ -345,10 +432,9 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).Return(types2.EntryWithCategory{}, assert.AnError)
 		mockDoguConverter := NewMockDoguConverter(t)
 		reader := &ConfigReader{
-			configuration:     &config.Configuration{Support: []config.SupportSource{}},
-			globalConfigRepo:  mockGlobalConfigRepo,
-			doguConverter:     mockDoguConverter,
-			externalConverter: mockExternalConverter,
+			configuration:    &config.Configuration{Support: []config.SupportSource{}},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{doguConverter: mockDoguConverter}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
 		}
 
 		// when
@@ -376,6 +462,7 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		reader := &ConfigReader{
 			configuration:    &config.Configuration{Support: []config.SupportSource{}},
 			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(),
 		}
 		// when
 		_, err := reader.Read(testCtx, &config.Configuration{Sources: testSources, Support: testSupportSources})
@@ -407,9 +494,9 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 		}, nil)
 
 		reader := &ConfigReader{
-			configuration:     &config.Configuration{Support: []config.SupportSource{}},
-			globalConfigRepo:  mockGlobalConfigRepo,
-			externalConverter: mockConverter,
+			configuration:    &config.Configuration{Support: []config.SupportSource{}},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{}, &ExternalsSourceReader{externalConverter: mockConverter}),
 		}
 
 		testSources := []config.Source{{Path: "externals", Type: "externals", Tag: "tag"}}
@@ -420,105 +507,156 @@ func TestConfigReader_readFromConfig(t *testing.T) {
 
 		expectedCategories := types2.Categories{
 			{Title: "Documentation", Entries: []types2.Entry{
-				{DisplayName: "ext1", Title: "ext1 Description", Target: types2.TARGET_EXTERNAL, Href: "https://my.url/ext1"},
+				{DisplayName: "ext1", Title: "ext1 Description", Target: types2.TARGET_EXTERNAL, Href: "https://my.url/ext1", ID: "ext1", Position: 1},
 			}},
 			{Title: "Support", Entries: []types2.Entry{
-				{Title: "supportSrc", Target: types2.TARGET_EXTERNAL, Href: "https://support.source"},
+				{Title: "supportSrc", Target: types2.TARGET_EXTERNAL, Href: "https://support.source", Position: 2},
 			}},
 		}
 		assert.Equal(t, expectedCategories, actual)
 	})
 }
 
-func TestConfigReader_dogusReader(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		// given
-		source := config.Source{
-			Path: "/dogu",
-			Type: "dogus",
-			Tag:  "warp",
+func TestConfigReader_readWithDuplicates(t *testing.T) {
+	redmineDogu := types2.Entry{DisplayName: "Redmine", Title: "Redmine", Href: "/redmine", Target: types2.TARGET_SELF, ID: "redmine", Source: types2.SourceDogu}
+	redmineExternal := types2.Entry{DisplayName: "Redmine (external)", Href: "/redmine", Target: types2.TARGET_EXTERNAL, Source: types2.SourceExternal}
+
+	setup := func(t *testing.T) *ConfigReader {
+		mockGlobalConfigRepo := NewMockGlobalConfigRepository(t)
+		globalConfig := registryconfig.GlobalConfig{
+			Config: registryconfig.CreateConfig(registryconfig.Entries{
+				"externals/redmine": "external",
+			}),
 		}
-		redmineEntryWithCategory := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
-		jenkinsEntryWithCategory := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps", types2.TARGET_SELF)
+		mockGlobalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+
+		mockExternalConverter := NewMockExternalConverter(t)
+		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("external").Return(types2.EntryWithCategory{Entry: redmineExternal, Category: "Links"}, nil)
+
 		mockDoguConverter := NewMockDoguConverter(t)
-		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readRedmineDogu(t), "warp").Return(redmineEntryWithCategory, nil)
-		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readJenkinsDogu(t), "warp").Return(jenkinsEntryWithCategory, nil)
+		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readRedmineDogu(t), "warp").Return(types2.EntryWithCategory{Entry: redmineDogu, Category: "Development Apps"}, nil)
+
 		versionRegistryMock := NewMockDoguVersionRegistry(t)
 		redmineVersion := parseVersion(t, "5.1.3-1")
-		jenkinsVersion := parseVersion(t, "2.452.2-1")
 		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *redmineVersion}
-		jenkinsDoguVersion := dogu.SimpleNameVersion{Name: "jenkins", Version: *jenkinsVersion}
-		currentDoguVersions := []dogu.SimpleNameVersion{redmineDoguVersion, jenkinsDoguVersion}
-		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(currentDoguVersions, nil)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(mock.Anything).Return([]dogu.SimpleNameVersion{redmineDoguVersion}, nil)
 		doguSpecRepoMock := NewMockLocalDoguRepo(t)
-		doguSpecRepoMock.EXPECT().GetAll(testCtx, currentDoguVersions).Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t), jenkinsDoguVersion: readJenkinsDogu(t)}, nil)
+		doguSpecRepoMock.EXPECT().GetAll(mock.Anything, []dogu.SimpleNameVersion{redmineDoguVersion}).Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t)}, nil)
 
-		reader := &ConfigReader{
-			configuration:       &config.Configuration{Support: []config.SupportSource{}},
-			doguConverter:       mockDoguConverter,
-			doguVersionRegistry: versionRegistryMock,
-			localDoguRepo:       doguSpecRepoMock,
+		return &ConfigReader{
+			configuration:    &config.Configuration{},
+			globalConfigRepo: mockGlobalConfigRepo,
+			sourceReaders:    NewSourceReaderRegistry(&DogusSourceReader{doguConverter: mockDoguConverter, doguVersionRegistry: versionRegistryMock, localDoguRepo: doguSpecRepoMock}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
 		}
+	}
+	externalsFirst := []config.Source{{Path: "externals", Type: "externals"}, {Path: "/dogu", Type: "dogus", Tag: "warp"}}
+
+	t.Run("should keep dogu entry by default regardless of source order", func(t *testing.T) {
+		// when
+		actual, err := setup(t).Read(testCtx, &config.Configuration{Sources: externalsFirst})
 
+		// then
+		require.NoError(t, err)
+		expectedDogu := redmineDogu
+		expectedDogu.Position = 2
+		expected := types2.Categories{{Title: "Development Apps", Entries: types2.Entries{expectedDogu}}}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should keep external entry if configured", func(t *testing.T) {
 		// when
-		categories, err := reader.dogusReader(testCtx, source)
+		actual, err := setup(t).Read(testCtx, &config.Configuration{Sources: externalsFirst, Merge: config.MergeConfig{Prefer: "external"}})
 
 		// then
 		require.NoError(t, err)
-		assert.Equal(t, 1, categories.Len())
-		assert.Equal(t, 2, len(categories[0].Entries))
+		expectedExternal := redmineExternal
+		expectedExternal.ID = "redmine"
+		expectedExternal.Position = 1
+		expected := types2.Categories{{Title: "Links", Entries: types2.Entries{expectedExternal}}}
+		assert.Equal(t, expected, actual)
 	})
+}
+
+func Test_applyEntryWeights(t *testing.T) {
+	// given
+	categories := types2.Categories{
+		{Title: "Development Apps", Entries: types2.Entries{{ID: "redmine"}, {ID: "jenkins", Weight: 5}}, Children: types2.Categories{
+			{Title: "CI", Entries: types2.Entries{{ID: "sonar"}, {}}},
+		}},
+	}
+
+	// when
+	applyEntryWeights(categories, map[string]int{"redmine": 10, "sonar": 20, "": 30})
+
+	// then
+	assert.Equal(t, 10, categories[0].Entries[0].Weight)
+	assert.Equal(t, 5, categories[0].Entries[1].Weight)
+	assert.Equal(t, 20, categories[0].Children[0].Entries[0].Weight)
+	assert.Equal(t, 0, categories[0].Children[0].Entries[1].Weight)
+}
 
-	t.Run("failed to get all current versions", func(t *testing.T) {
+func TestConfigReader_createCategories(t *testing.T) {
+	t.Run("should create nested categories with configured order", func(t *testing.T) {
 		// given
-		source := config.Source{
-			Path: "/dogu",
-			Type: "dogus",
-			Tag:  "warp",
-		}
-		versionRegistryMock := NewMockDoguVersionRegistry(t)
-		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(nil, assert.AnError)
 		reader := &ConfigReader{
-			doguVersionRegistry: versionRegistryMock,
-			configuration:       &config.Configuration{Support: []config.SupportSource{}},
+			configuration: &config.Configuration{NestedCategories: true, Order: config.Order{"Development Apps": 100, "Development Apps/CI": 10}},
 		}
+		redmine := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
+		jenkins := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps/CI", types2.TARGET_SELF)
+		nexus := getEntryWithCategory("Nexus", "/nexus", "Nexus", "Development Apps/CI", types2.TARGET_SELF)
 
 		// when
-		_, err := reader.dogusReader(testCtx, source)
+		actual := reader.createCategories([]types2.EntryWithCategory{nexus, jenkins, redmine}, types2.DefaultMergePolicy)
 
 		// then
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to get all current dogu versions")
+		expected := types2.Categories{
+			{Title: "Development Apps", Order: 100, Entries: types2.Entries{redmine.Entry}, Children: types2.Categories{
+				{Title: "CI", Order: 10, Entries: types2.Entries{jenkins.Entry, nexus.Entry}},
+			}},
+		}
+		assert.Equal(t, expected, actual)
 	})
 
-	t.Run("failed to get dogus of currents", func(t *testing.T) {
+	t.Run("should keep titles containing a slash without nested categories", func(t *testing.T) {
 		// given
-		source := config.Source{
-			Path: "/dogu",
-			Type: "dogus",
-			Tag:  "warp",
-		}
-		redmineVersion := parseVersion(t, "5.1.3-1")
-		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *redmineVersion}
-		currentDoguVersions := []dogu.SimpleNameVersion{redmineDoguVersion}
-		versionRegistryMock := NewMockDoguVersionRegistry(t)
-		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(currentDoguVersions, nil)
-		doguSpecMock := NewMockLocalDoguRepo(t)
-		doguSpecMock.EXPECT().GetAll(testCtx, currentDoguVersions).Return(nil, assert.AnError)
 		reader := &ConfigReader{
-			doguVersionRegistry: versionRegistryMock,
-			localDoguRepo:       doguSpecMock,
-			configuration:       &config.Configuration{Support: []config.SupportSource{}},
+			configuration: &config.Configuration{Order: config.Order{"CI/CD": 50, "Documentation": 10}},
 		}
+		jenkins := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "CI/CD", types2.TARGET_SELF)
+		docs := getEntryWithCategory("Docs", "/docs", "Docs", "Documentation", types2.TARGET_SELF)
+		redmine := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
 
 		// when
-		_, err := reader.dogusReader(testCtx, source)
+		actual := reader.createCategories([]types2.EntryWithCategory{redmine, docs, jenkins}, types2.DefaultMergePolicy)
 
 		// then
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to get all dogu specs with current versions")
+		expected := types2.Categories{
+			{Title: "CI/CD", Order: 50, Entries: types2.Entries{jenkins.Entry}},
+			{Title: "Documentation", Order: 10, Entries: types2.Entries{docs.Entry}},
+			{Title: "Development Apps", Order: 0, Entries: types2.Entries{redmine.Entry}},
+		}
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, expected, actual.Flatten())
+	})
+
+	t.Run("should keep entries with the same href but different ids if merged by id", func(t *testing.T) {
+		// given
+		reader := &ConfigReader{configuration: &config.Configuration{NestedCategories: true}}
+		jenkins := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps/CI", types2.TARGET_SELF)
+		jenkins.Entry.ID = "jenkins"
+		jenkinsLegacy := getEntryWithCategory("Jenkins Legacy", "/jenkins", "Jenkins", "Development Apps/CI", types2.TARGET_SELF)
+		jenkinsLegacy.Entry.ID = "jenkins-legacy"
+
+		// when
+		actual := reader.createCategories([]types2.EntryWithCategory{jenkins, jenkinsLegacy}, types2.MergePolicy{Key: types2.MergeByID, Prefer: types2.SourceDogu})
+
+		// then
+		require.Len(t, actual, 1)
+		require.Len(t, actual[0].Children, 1)
+		assert.Equal(t, types2.Entries{jenkins.Entry, jenkinsLegacy.Entry}, actual[0].Children[0].Entries)
 	})
 }
+
 func getEntryWithCategory(displayName string, href string, title string, category string, target types2.Target) types2.EntryWithCategory {
 	return types2.EntryWithCategory{Entry: types2.Entry{
 		DisplayName: displayName,
@@ -527,3 +665,104 @@ func getEntryWithCategory(displayName string, href string, title string, categor
 		Target:      target,
 	}, Category: category}
 }
+
+func TestConfigReader_readSources(t *testing.T) {
+	t.Run("should not wait for slow source longer than its timeout", func(t *testing.T) {
+		// given
+		globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"externals/cloudogu": "URL: https://www.cloudogu.com"})
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(mock.Anything).
+			RunAndReturn(func(ctx context.Context) ([]dogu.SimpleNameVersion, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		mockExternalConverter := NewMockExternalConverter(t)
+		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("URL: https://www.cloudogu.com").
+			Return(types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com"}, Category: "External"}, nil)
+		reader := &ConfigReader{
+			configuration: &config.Configuration{},
+			sourceReaders: NewSourceReaderRegistry(&DogusSourceReader{doguVersionRegistry: versionRegistryMock}, &ExternalsSourceReader{externalConverter: mockExternalConverter}),
+		}
+		sources := []config.Source{{Path: "/dogu", Type: "dogus", Timeout: "10ms"}, {Path: "externals", Type: "externals"}}
+
+		// when
+		results := reader.readSources(testCtx, sources, globalConfig, nil)
+
+		// then
+		require.Len(t, results, 2)
+		require.Error(t, results[0].err)
+		assert.ErrorIs(t, results[0].err, context.DeadlineExceeded)
+		assert.ErrorContains(t, results[0].err, "failed to read source dogus:/dogu within 10ms")
+		require.NoError(t, results[1].err)
+		assert.Equal(t, "External", results[1].entries[0].Category)
+	})
+
+	t.Run("should return panic of source as error", func(t *testing.T) {
+		// given
+		globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"externals/cloudogu": "URL: https://www.cloudogu.com"})
+		mockExternalConverter := NewMockExternalConverter(t)
+		mockExternalConverter.EXPECT().ReadAndUnmarshalExternal(mock.Anything).
+			RunAndReturn(func(string) (types2.EntryWithCategory, error) { panic("broken") })
+		reader := &ConfigReader{configuration: &config.Configuration{}, sourceReaders: NewSourceReaderRegistry(&DogusSourceReader{}, &ExternalsSourceReader{externalConverter: mockExternalConverter})}
+
+		// when
+		results := reader.readSources(testCtx, []config.Source{{Path: "externals", Type: "externals"}}, globalConfig, nil)
+
+		// then
+		require.Error(t, results[0].err)
+		assert.ErrorContains(t, results[0].err, "panic while reading source externals:externals: broken")
+	})
+
+	t.Run("should skip support entry config sources", func(t *testing.T) {
+		reader := &ConfigReader{configuration: &config.Configuration{}}
+
+		results := reader.readSources(testCtx, []config.Source{{Type: supportEntryConfigSourceType}}, registryconfig.GlobalConfig{}, nil)
+
+		assert.Equal(t, []sourceResult{{}}, results)
+	})
+}
+
+func TestConfigReader_Read_declarationOrder(t *testing.T) {
+	// given
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.CreateGlobalConfig(registryconfig.Entries{
+		"slow/link":  "URL: https://slow.example.com",
+		"quick/link": "URL: https://quick.example.com",
+	}), nil)
+	mockExternalConverter := NewMockExternalConverter(t)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("URL: https://slow.example.com").
+		RunAndReturn(func(string) (types2.EntryWithCategory, error) {
+			time.Sleep(20 * time.Millisecond)
+			return types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Slow", Href: "https://slow.example.com"}, Category: "External"}, nil
+		})
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("URL: https://quick.example.com").
+		Return(types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Quick", Href: "https://quick.example.com"}, Category: "External"}, nil)
+	reader := NewConfigReader(&config.Configuration{}, globalConfigRepoMock, NewSourceReaderRegistry(&ExternalsSourceReader{externalConverter: mockExternalConverter}), nil)
+	configuration := &config.Configuration{
+		Sources: []config.Source{{Path: "slow", Type: "externals"}, {Path: "quick", Type: "externals"}},
+		Sorting: config.SortingConfig{Mode: "source"},
+	}
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	require.Len(t, categories[0].Entries, 2)
+	assert.Equal(t, "Slow", categories[0].Entries[0].DisplayName)
+	assert.Equal(t, "Quick", categories[0].Entries[1].DisplayName)
+}
+
+func Test_sourceTimeout(t *testing.T) {
+	assert.Equal(t, config.DefaultSourceTimeout, sourceTimeout(config.Source{}))
+	assert.Equal(t, 5*time.Second, sourceTimeout(config.Source{Timeout: "5s"}))
+	assert.Equal(t, config.DefaultSourceTimeout, sourceTimeout(config.Source{Timeout: "soon"}))
+	assert.Equal(t, config.DefaultSourceTimeout, sourceTimeout(config.Source{Timeout: "-1s"}))
+}
+
+func Test_sourceKey(t *testing.T) {
+	assert.Equal(t, "dogus:/dogu", sourceKey(config.Source{Type: "dogus", Path: "/dogu"}))
+	assert.Equal(t, "dogus:/dogu#warp", sourceKey(config.Source{Type: "dogus", Path: "/dogu", Tag: "warp"}))
+	assert.NotEqual(t, sourceKey(config.Source{Type: "dogus", Path: "/dogu", Tag: "warp"}), sourceKey(config.Source{Type: "dogus", Path: "/dogu", Tag: "admin"}))
+}
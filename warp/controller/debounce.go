@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// warpMenuRequestName is the name of the single request that generates the warp menu of a namespace.
+const warpMenuRequestName = "warp-menu"
+
+// debouncedEventHandler maps all events to the single warp menu request of their namespace. The request is queued
+// after no event occurred for the debounce window, but at the latest after the max delay since the first event.
+type debouncedEventHandler struct {
+	window     time.Duration
+	maxDelay   time.Duration
+	clock      clock.WithDelayedExecution
+	mutex      sync.Mutex
+	debouncers map[reconcile.Request]*debouncer
+}
+
+func newDebouncedEventHandler(window time.Duration, maxDelay time.Duration) *debouncedEventHandler {
+	return &debouncedEventHandler{
+		window:     window,
+		maxDelay:   maxDelay,
+		clock:      clock.RealClock{},
+		debouncers: map[reconcile.Request]*debouncer{},
+	}
+}
+
+func (h *debouncedEventHandler) Create(_ context.Context, e event.TypedCreateEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.trigger(e.Object, q)
+}
+
+func (h *debouncedEventHandler) Update(_ context.Context, e event.TypedUpdateEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.trigger(e.ObjectNew, q)
+}
+
+func (h *debouncedEventHandler) Delete(_ context.Context, e event.TypedDeleteEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.trigger(e.Object, q)
+}
+
+func (h *debouncedEventHandler) Generic(_ context.Context, e event.TypedGenericEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.trigger(e.Object, q)
+}
+
+func (h *debouncedEventHandler) trigger(object client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: object.GetNamespace(), Name: warpMenuRequestName}}
+	if h.window <= 0 {
+		q.Add(request)
+		return
+	}
+
+	h.mutex.Lock()
+	requestDebouncer, ok := h.debouncers[request]
+	if !ok {
+		requestDebouncer = newDebouncer(h.clock, h.window, h.maxDelay, func() { q.Add(request) })
+		h.debouncers[request] = requestDebouncer
+	}
+	h.mutex.Unlock()
+
+	requestDebouncer.trigger()
+}
+
+// debouncer calls fire once no trigger occurred for the window or the max delay since the first trigger passed. A max
+// delay of zero does not limit the delay.
+type debouncer struct {
+	window   time.Duration
+	maxDelay time.Duration
+	fire     func()
+	clock    clock.WithDelayedExecution
+	mutex    sync.Mutex
+	timer    clock.Timer
+	deadline time.Time
+	// generation identifies the current timer. A replaced timer that already expired does not fire.
+	generation uint64
+}
+
+func newDebouncer(clock clock.WithDelayedExecution, window time.Duration, maxDelay time.Duration, fire func()) *debouncer {
+	return &debouncer{window: window, maxDelay: maxDelay, fire: fire, clock: clock}
+}
+
+func (d *debouncer) trigger() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.clock.Now()
+	if d.timer == nil && d.maxDelay > 0 {
+		d.deadline = now.Add(d.maxDelay)
+	}
+
+	fireAt := now.Add(d.window)
+	if d.maxDelay > 0 && fireAt.After(d.deadline) {
+		fireAt = d.deadline
+	}
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.generation++
+	generation := d.generation
+	d.timer = d.clock.AfterFunc(fireAt.Sub(now), func() { d.run(generation) })
+}
+
+func (d *debouncer) run(generation uint64) {
+	d.mutex.Lock()
+	if generation != d.generation {
+		d.mutex.Unlock()
+		return
+	}
+	d.timer = nil
+	d.mutex.Unlock()
+
+	d.fire()
+}
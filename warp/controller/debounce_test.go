@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDebouncer(t *testing.T) {
+	t.Run("should fire once for a burst of triggers", func(t *testing.T) {
+		// given
+		var fired atomic.Int32
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		sut := newDebouncer(fakeClock, 50*time.Millisecond, time.Second, func() { fired.Add(1) })
+
+		// when
+		for i := 0; i < 5; i++ {
+			sut.trigger()
+			fakeClock.Step(10 * time.Millisecond)
+		}
+
+		// then
+		fakeClock.Step(39 * time.Millisecond)
+		assert.Equal(t, int32(0), fired.Load())
+		fakeClock.Step(time.Millisecond)
+		assert.Equal(t, int32(1), fired.Load())
+		fakeClock.Step(time.Second)
+		assert.Equal(t, int32(1), fired.Load())
+	})
+
+	t.Run("should fire after max delay for continuous triggers", func(t *testing.T) {
+		// given
+		var fired atomic.Int32
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		sut := newDebouncer(fakeClock, 50*time.Millisecond, 100*time.Millisecond, func() { fired.Add(1) })
+
+		// when
+		for i := 0; i < 30; i++ {
+			sut.trigger()
+			fakeClock.Step(10 * time.Millisecond)
+		}
+
+		// then
+		assert.Equal(t, int32(3), fired.Load())
+	})
+
+	t.Run("should fire again for later triggers", func(t *testing.T) {
+		// given
+		var fired atomic.Int32
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		sut := newDebouncer(fakeClock, 10*time.Millisecond, 0, func() { fired.Add(1) })
+
+		// when
+		sut.trigger()
+		fakeClock.Step(10 * time.Millisecond)
+		require.Equal(t, int32(1), fired.Load())
+		sut.trigger()
+		fakeClock.Step(10 * time.Millisecond)
+
+		// then
+		assert.Equal(t, int32(2), fired.Load())
+	})
+
+	t.Run("should fire with real clock", func(t *testing.T) {
+		// given
+		var fired atomic.Int32
+		sut := newDebouncer(clock.RealClock{}, time.Millisecond, 0, func() { fired.Add(1) })
+
+		// when
+		sut.trigger()
+
+		// then
+		assert.Eventually(t, func() bool { return fired.Load() == 1 }, 5*time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestDebouncedEventHandler(t *testing.T) {
+	expectedRequest := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: warpMenuRequestName}}
+
+	t.Run("should map all events to one request", func(t *testing.T) {
+		// given
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+		fakeClock := testingclock.NewFakeClock(time.Now())
+		sut := newDebouncedEventHandler(20*time.Millisecond, time.Second)
+		sut.clock = fakeClock
+
+		// when
+		sut.Create(testCtx, event.CreateEvent{Object: newConfigMapWithName("dogu-spec-redmine")}, queue)
+		sut.Update(testCtx, event.UpdateEvent{ObjectOld: newConfigMapWithName("global-config"), ObjectNew: newConfigMapWithName("global-config")}, queue)
+		sut.Delete(testCtx, event.DeleteEvent{Object: newConfigMapWithName("dogu-spec-jenkins")}, queue)
+		sut.Generic(testCtx, event.GenericEvent{Object: newConfigMapWithName("k8s-ces-warp-config")}, queue)
+
+		// then
+		assert.Equal(t, 0, queue.Len())
+		fakeClock.Step(20 * time.Millisecond)
+		require.Equal(t, 1, queue.Len())
+		request, _ := queue.Get()
+		assert.Equal(t, expectedRequest, request)
+	})
+
+	t.Run("should queue request immediately without window", func(t *testing.T) {
+		// given
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+		sut := newDebouncedEventHandler(0, 0)
+
+		// when
+		sut.Create(testCtx, event.CreateEvent{Object: newConfigMapWithName("dogu-spec-redmine")}, queue)
+		sut.Create(testCtx, event.CreateEvent{Object: newConfigMapWithName("dogu-spec-jenkins")}, queue)
+
+		// then
+		require.Equal(t, 1, queue.Len())
+		request, _ := queue.Get()
+		assert.Equal(t, expectedRequest, request)
+	})
+}
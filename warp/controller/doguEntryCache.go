@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DefaultDoguEntryCacheSize limits the number of converted dogu entries kept in memory.
+const DefaultDoguEntryCacheSize = 500
+
+var (
+	doguEntryCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "warp_menu_dogu_cache_hits_total",
+		Help: "Number of dogus whose warp menu entry was taken from the cache.",
+	})
+	doguEntryCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "warp_menu_dogu_cache_misses_total",
+		Help: "Number of dogus whose descriptor was loaded and converted to a warp menu entry.",
+	})
+	doguEntryCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "warp_menu_dogu_cache_entries",
+		Help: "Number of converted dogu entries in the cache.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(doguEntryCacheHits, doguEntryCacheMisses, doguEntryCacheSize)
+}
+
+// doguEntryCacheKey identifies the conversion of a dogu version with a tag.
+type doguEntryCacheKey struct {
+	name    string
+	version string
+	tag     string
+}
+
+// cachedDoguEntry is the converted entry of a dogu. Dogus without an entry in the warp menu are cached as not visible,
+// so their descriptor is not loaded again.
+type cachedDoguEntry struct {
+	key     doguEntryCacheKey
+	entry   types2.EntryWithCategory
+	visible bool
+}
+
+// DoguEntryCache keeps converted dogu entries by name, version and tag, so only descriptors of changed dogus are
+// loaded and converted. It holds at most maxSize entries and evicts the least recently used one.
+type DoguEntryCache struct {
+	mutex   sync.Mutex
+	maxSize int
+	entries map[doguEntryCacheKey]*list.Element
+	order   *list.List
+}
+
+// NewDoguEntryCache creates an empty cache with the given maximum number of entries.
+func NewDoguEntryCache(maxSize int) *DoguEntryCache {
+	return &DoguEntryCache{
+		maxSize: maxSize,
+		entries: map[doguEntryCacheKey]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func newDoguEntryCacheKey(version dogu.SimpleNameVersion, tag string) doguEntryCacheKey {
+	return doguEntryCacheKey{name: string(version.Name), version: version.Version.Raw, tag: tag}
+}
+
+// Get returns the cached entry of the dogu version and whether it is shown in the warp menu.
+func (c *DoguEntryCache) Get(version dogu.SimpleNameVersion, tag string) (types2.EntryWithCategory, bool, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[newDoguEntryCacheKey(version, tag)]
+	if !ok {
+		doguEntryCacheMisses.Inc()
+		return types2.EntryWithCategory{}, false, false
+	}
+
+	doguEntryCacheHits.Inc()
+	c.order.MoveToFront(element)
+	cached := element.Value.(*cachedDoguEntry)
+	return cached.entry, cached.visible, true
+}
+
+// Add stores the converted entry of the dogu version.
+func (c *DoguEntryCache) Add(version dogu.SimpleNameVersion, tag string, entry types2.EntryWithCategory, visible bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := newDoguEntryCacheKey(version, tag)
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cachedDoguEntry{key: key, entry: entry, visible: visible}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cachedDoguEntry{key: key, entry: entry, visible: visible})
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		c.remove(c.order.Back())
+	}
+	doguEntryCacheSize.Set(float64(c.order.Len()))
+}
+
+// Retain removes all entries of dogu versions that are not current anymore.
+func (c *DoguEntryCache) Retain(currentVersions []dogu.SimpleNameVersion) {
+	current := make(map[doguEntryCacheKey]bool, len(currentVersions))
+	for _, version := range currentVersions {
+		current[newDoguEntryCacheKey(version, "")] = true
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, element := range c.entries {
+		if !current[doguEntryCacheKey{name: key.name, version: key.version}] {
+			c.remove(element)
+		}
+	}
+	doguEntryCacheSize.Set(float64(c.order.Len()))
+}
+
+// Len returns the number of cached entries.
+func (c *DoguEntryCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *DoguEntryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cachedDoguEntry).key)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoguEntryCache(t *testing.T) {
+	redmine := dogu.SimpleNameVersion{Name: "redmine", Version: *parseVersion(t, "5.1.3-1")}
+	upgradedRedmine := dogu.SimpleNameVersion{Name: "redmine", Version: *parseVersion(t, "5.1.4-1")}
+	jenkins := dogu.SimpleNameVersion{Name: "jenkins", Version: *parseVersion(t, "2.452.2-1")}
+	scm := dogu.SimpleNameVersion{Name: "scm", Version: *parseVersion(t, "3.1.0-1")}
+	redmineEntry := types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Redmine", Href: "/redmine"}, Category: "Development Apps"}
+
+	t.Run("should return added entry for same version and tag", func(t *testing.T) {
+		// given
+		cache := NewDoguEntryCache(10)
+		cache.Add(redmine, "warp", redmineEntry, true)
+
+		// when
+		entry, visible, ok := cache.Get(redmine, "warp")
+		_, _, okOtherTag := cache.Get(redmine, "other")
+		_, _, okOtherVersion := cache.Get(upgradedRedmine, "warp")
+
+		// then
+		assert.True(t, ok)
+		assert.True(t, visible)
+		assert.Equal(t, redmineEntry, entry)
+		assert.False(t, okOtherTag)
+		assert.False(t, okOtherVersion)
+	})
+
+	t.Run("should cache dogus without warp menu entry", func(t *testing.T) {
+		cache := NewDoguEntryCache(10)
+		cache.Add(jenkins, "warp", types2.EntryWithCategory{}, false)
+
+		_, visible, ok := cache.Get(jenkins, "warp")
+
+		assert.True(t, ok)
+		assert.False(t, visible)
+	})
+
+	t.Run("should evict least recently used entry", func(t *testing.T) {
+		// given
+		cache := NewDoguEntryCache(2)
+		cache.Add(redmine, "warp", redmineEntry, true)
+		cache.Add(jenkins, "warp", types2.EntryWithCategory{}, true)
+		cache.Get(redmine, "warp")
+
+		// when
+		cache.Add(scm, "warp", types2.EntryWithCategory{}, true)
+
+		// then
+		assert.Equal(t, 2, cache.Len())
+		_, _, okRedmine := cache.Get(redmine, "warp")
+		_, _, okJenkins := cache.Get(jenkins, "warp")
+		assert.True(t, okRedmine)
+		assert.False(t, okJenkins)
+	})
+
+	t.Run("should remove entries of versions that are not current", func(t *testing.T) {
+		// given
+		cache := NewDoguEntryCache(10)
+		cache.Add(redmine, "warp", redmineEntry, true)
+		cache.Add(redmine, "other", redmineEntry, true)
+		cache.Add(jenkins, "warp", types2.EntryWithCategory{}, true)
+
+		// when
+		cache.Retain([]dogu.SimpleNameVersion{upgradedRedmine, jenkins})
+
+		// then
+		assert.Equal(t, 1, cache.Len())
+		_, _, ok := cache.Get(jenkins, "warp")
+		assert.True(t, ok)
+	})
+
+	t.Run("should count hits and misses", func(t *testing.T) {
+		// given
+		cache := NewDoguEntryCache(10)
+		cache.Add(redmine, "warp", redmineEntry, true)
+		hits := testutil.ToFloat64(doguEntryCacheHits)
+		misses := testutil.ToFloat64(doguEntryCacheMisses)
+
+		// when
+		cache.Get(redmine, "warp")
+		cache.Get(jenkins, "warp")
+		cache.Get(scm, "warp")
+
+		// then
+		assert.Equal(t, hits+1, testutil.ToFloat64(doguEntryCacheHits))
+		assert.Equal(t, misses+2, testutil.ToFloat64(doguEntryCacheMisses))
+		assert.Equal(t, float64(1), testutil.ToFloat64(doguEntryCacheSize))
+	})
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const dogusSourceType = "dogus"
+
+// DogusSourceReader reads the installed dogus from the dogu registry.
+type DogusSourceReader struct {
+	doguVersionRegistry DoguVersionRegistry
+	localDoguRepo       LocalDoguRepo
+	doguConverter       DoguConverter
+	doguCache           *DoguEntryCache
+}
+
+func NewDogusSourceReader(doguVersionRegistry DoguVersionRegistry, localDoguRepo LocalDoguRepo, doguCache *DoguEntryCache) *DogusSourceReader {
+	return &DogusSourceReader{
+		doguVersionRegistry: doguVersionRegistry,
+		localDoguRepo:       localDoguRepo,
+		doguConverter:       &types2.DoguConverter{},
+		doguCache:           doguCache,
+	}
+}
+
+func (r *DogusSourceReader) Type() string {
+	return dogusSourceType
+}
+
+func (r *DogusSourceReader) Schema() SourceSchema {
+	return SourceSchema{OptionalFields: []string{sourceFieldPath, sourceFieldTag}}
+}
+
+// WatchTriggers returns that the source depends on the installed dogus.
+func (r *DogusSourceReader) WatchTriggers(config.Source) SourceWatchTriggers {
+	return SourceWatchTriggers{DoguVersions: true}
+}
+
+// Read converts the current versions of all dogus with the tag of the source to entries. Only descriptors of dogu
+// versions that are not in the dogu cache are loaded and converted.
+func (r *DogusSourceReader) Read(ctx context.Context, source config.Source, _ libconfig.GlobalConfig, _ error) (SourceResult, error) {
+	ctrl.Log.Info(fmt.Sprintf("Read dogus from %s for warp menu", source.Path))
+	allCurrentDoguVersions, err := r.doguVersionRegistry.GetCurrentOfAll(ctx)
+	if err != nil {
+		return SourceResult{}, fmt.Errorf("failed to get all current dogu versions: %w", err)
+	}
+
+	if len(allCurrentDoguVersions) == 0 {
+		return SourceResult{}, nil
+	}
+
+	// the versions are sorted in a copy, because several dogu sources may be read at the same time
+	allCurrentDoguVersions = slices.Clone(allCurrentDoguVersions)
+	sort.Slice(allCurrentDoguVersions, func(i, j int) bool {
+		return allCurrentDoguVersions[i].Name < allCurrentDoguVersions[j].Name
+	})
+
+	converted := make(map[dogu.SimpleNameVersion]convertedDogu, len(allCurrentDoguVersions))
+	var changedDoguVersions []dogu.SimpleNameVersion
+	for _, version := range allCurrentDoguVersions {
+		if r.doguCache != nil {
+			if entry, visible, ok := r.doguCache.Get(version, source.Tag); ok {
+				converted[version] = convertedDogu{entry: entry, visible: visible}
+				continue
+			}
+		}
+		changedDoguVersions = append(changedDoguVersions, version)
+	}
+
+	if len(changedDoguVersions) > 0 {
+		changedDogus, err := r.localDoguRepo.GetAll(ctx, changedDoguVersions)
+		if err != nil {
+			return SourceResult{}, fmt.Errorf("failed to get all dogu specs with current versions: %w", err)
+		}
+
+		for version, changedDogu := range changedDogus {
+			doguEntry, err := r.doguConverter.CreateEntryWithCategoryFromDogu(changedDogu, source.Tag)
+			visible := err == nil && doguEntry.Entry.Title != ""
+			converted[version] = convertedDogu{entry: doguEntry, visible: visible}
+			if r.doguCache != nil {
+				r.doguCache.Add(version, source.Tag, doguEntry, visible)
+			}
+		}
+	}
+
+	if r.doguCache != nil {
+		r.doguCache.Retain(allCurrentDoguVersions)
+	}
+
+	var doguCategories []types2.EntryWithCategory
+	for _, version := range allCurrentDoguVersions {
+		doguEntry, ok := converted[version]
+		if ok && doguEntry.visible {
+			ctrl.Log.Info(fmt.Sprintf("Add dogu %s with category %s", version.Name, doguEntry.entry.Category))
+			doguCategories = append(doguCategories, doguEntry.entry)
+		}
+	}
+
+	return SourceResult{Entries: doguCategories}, nil
+}
+
+// convertedDogu is the warp menu entry of a dogu and whether it is shown in the warp menu.
+type convertedDogu struct {
+	entry   types2.EntryWithCategory
+	visible bool
+}
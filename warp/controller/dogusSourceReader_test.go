@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/cesapp-lib/core"
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDogusSourceReader_Read(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		source := config.Source{
+			Path: "/dogu",
+			Type: "dogus",
+			Tag:  "warp",
+		}
+		redmineEntryWithCategory := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
+		jenkinsEntryWithCategory := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps", types2.TARGET_SELF)
+		mockDoguConverter := NewMockDoguConverter(t)
+		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readRedmineDogu(t), "warp").Return(redmineEntryWithCategory, nil)
+		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readJenkinsDogu(t), "warp").Return(jenkinsEntryWithCategory, nil)
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		redmineVersion := parseVersion(t, "5.1.3-1")
+		jenkinsVersion := parseVersion(t, "2.452.2-1")
+		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *redmineVersion}
+		jenkinsDoguVersion := dogu.SimpleNameVersion{Name: "jenkins", Version: *jenkinsVersion}
+		currentDoguVersions := []dogu.SimpleNameVersion{redmineDoguVersion, jenkinsDoguVersion}
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(currentDoguVersions, nil)
+		doguSpecRepoMock := NewMockLocalDoguRepo(t)
+		doguSpecRepoMock.EXPECT().GetAll(testCtx, []dogu.SimpleNameVersion{jenkinsDoguVersion, redmineDoguVersion}).Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t), jenkinsDoguVersion: readJenkinsDogu(t)}, nil)
+
+		reader := &DogusSourceReader{doguConverter: mockDoguConverter, doguVersionRegistry: versionRegistryMock, localDoguRepo: doguSpecRepoMock}
+
+		// when
+		result, err := reader.Read(testCtx, source, registryconfig.GlobalConfig{}, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, result.Entries, 2)
+	})
+
+	t.Run("should only load and convert changed dogus with cache", func(t *testing.T) {
+		// given
+		source := config.Source{Path: "/dogu", Type: "dogus", Tag: "warp"}
+		redmineEntryWithCategory := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
+		jenkinsEntryWithCategory := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps", types2.TARGET_SELF)
+		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *parseVersion(t, "5.1.3-1")}
+		jenkinsDoguVersion := dogu.SimpleNameVersion{Name: "jenkins", Version: *parseVersion(t, "2.452.2-1")}
+		upgradedJenkinsDoguVersion := dogu.SimpleNameVersion{Name: "jenkins", Version: *parseVersion(t, "2.452.3-1")}
+
+		mockDoguConverter := NewMockDoguConverter(t)
+		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readRedmineDogu(t), "warp").Return(redmineEntryWithCategory, nil).Once()
+		mockDoguConverter.EXPECT().CreateEntryWithCategoryFromDogu(readJenkinsDogu(t), "warp").Return(jenkinsEntryWithCategory, nil).Twice()
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]dogu.SimpleNameVersion{redmineDoguVersion, jenkinsDoguVersion}, nil).Once()
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]dogu.SimpleNameVersion{redmineDoguVersion, upgradedJenkinsDoguVersion}, nil).Once()
+		doguSpecRepoMock := NewMockLocalDoguRepo(t)
+		doguSpecRepoMock.EXPECT().GetAll(testCtx, []dogu.SimpleNameVersion{jenkinsDoguVersion, redmineDoguVersion}).
+			Return(map[dogu.SimpleNameVersion]*core.Dogu{redmineDoguVersion: readRedmineDogu(t), jenkinsDoguVersion: readJenkinsDogu(t)}, nil).Once()
+		doguSpecRepoMock.EXPECT().GetAll(testCtx, []dogu.SimpleNameVersion{upgradedJenkinsDoguVersion}).
+			Return(map[dogu.SimpleNameVersion]*core.Dogu{upgradedJenkinsDoguVersion: readJenkinsDogu(t)}, nil).Once()
+
+		doguCache := NewDoguEntryCache(DefaultDoguEntryCacheSize)
+		reader := &DogusSourceReader{doguConverter: mockDoguConverter, doguVersionRegistry: versionRegistryMock, localDoguRepo: doguSpecRepoMock, doguCache: doguCache}
+
+		// when
+		_, err := reader.Read(testCtx, source, registryconfig.GlobalConfig{}, nil)
+		require.NoError(t, err)
+		result, err := reader.Read(testCtx, source, registryconfig.GlobalConfig{}, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, result.Entries, 2)
+		assert.Equal(t, 2, doguCache.Len())
+	})
+
+	t.Run("failed to get all current versions", func(t *testing.T) {
+		// given
+		source := config.Source{
+			Path: "/dogu",
+			Type: "dogus",
+			Tag:  "warp",
+		}
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(nil, assert.AnError)
+		reader := &DogusSourceReader{doguVersionRegistry: versionRegistryMock}
+
+		// when
+		_, err := reader.Read(testCtx, source, registryconfig.GlobalConfig{}, nil)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get all current dogu versions")
+	})
+
+	t.Run("failed to get dogus of currents", func(t *testing.T) {
+		// given
+		source := config.Source{
+			Path: "/dogu",
+			Type: "dogus",
+			Tag:  "warp",
+		}
+		redmineVersion := parseVersion(t, "5.1.3-1")
+		redmineDoguVersion := dogu.SimpleNameVersion{Name: "redmine", Version: *redmineVersion}
+		currentDoguVersions := []dogu.SimpleNameVersion{redmineDoguVersion}
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(currentDoguVersions, nil)
+		doguSpecMock := NewMockLocalDoguRepo(t)
+		doguSpecMock.EXPECT().GetAll(testCtx, currentDoguVersions).Return(nil, assert.AnError)
+		reader := &DogusSourceReader{doguVersionRegistry: versionRegistryMock, localDoguRepo: doguSpecMock}
+
+		// when
+		_, err := reader.Read(testCtx, source, registryconfig.GlobalConfig{}, nil)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get all dogu specs with current versions")
+	})
+}
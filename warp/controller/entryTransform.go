@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// entryTransform is a transform rule of the warp config with its compiled name pattern and parsed target.
+type entryTransform struct {
+	rule        config.TransformRule
+	namePattern *regexp.Regexp
+	target      types2.Target
+}
+
+// newEntryTransforms compiles the transform rules. Rules with an invalid name pattern are logged and skipped.
+func newEntryTransforms(rules []config.TransformRule) []entryTransform {
+	transforms := make([]entryTransform, 0, len(rules))
+	for i, rule := range rules {
+		transform := entryTransform{rule: rule}
+		if rule.Match.Name != "" {
+			namePattern, err := regexp.Compile(rule.Match.Name)
+			if err != nil {
+				ctrl.Log.Info(fmt.Sprintf("Invalid name pattern of transform rule %d, skipping rule: %s", i, err.Error()))
+				continue
+			}
+			transform.namePattern = namePattern
+		}
+		// the target was validated when the warp config was read
+		if target, err := types2.ParseTarget(rule.Target); err == nil {
+			transform.target = target
+		}
+		transforms = append(transforms, transform)
+	}
+	return transforms
+}
+
+// applyEntryTransforms applies the transforms in their order to the entries of a source in the menu of the host. Every
+// rule sees the result of the previous rules. Hidden entries are removed.
+func applyEntryTransforms(transforms []entryTransform, host string, sourceType string, entries []types2.EntryWithCategory) []types2.EntryWithCategory {
+	if len(transforms) == 0 {
+		return entries
+	}
+
+	result := make([]types2.EntryWithCategory, 0, len(entries))
+	for _, entry := range entries {
+		hidden := false
+		for _, transform := range transforms {
+			if !transform.matches(host, sourceType, entry) {
+				continue
+			}
+			if transform.rule.Hide {
+				hidden = true
+				break
+			}
+			entry = transform.apply(entry)
+		}
+
+		if !hidden {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (transform entryTransform) matches(host string, sourceType string, entry types2.EntryWithCategory) bool {
+	match := transform.rule.Match
+	if match.Host != "" && !matchesHost(match.Host, host) {
+		return false
+	}
+	if match.Source != "" && match.Source != sourceType {
+		return false
+	}
+	if match.Category != "" && match.Category != entry.Category {
+		return false
+	}
+	if match.Tag != "" && !slices.Contains(entry.Tags, match.Tag) {
+		return false
+	}
+	return transform.namePattern == nil || transform.namePattern.MatchString(entry.Entry.DisplayName)
+}
+
+// matchesHost returns whether the host condition matches the host of the menu. A condition starting with "!" matches
+// all menus except the one of the host.
+func matchesHost(condition string, host string) bool {
+	if excludedHost, ok := strings.CutPrefix(condition, "!"); ok {
+		return !strings.EqualFold(excludedHost, host)
+	}
+	return strings.EqualFold(condition, host)
+}
+
+func (transform entryTransform) apply(entry types2.EntryWithCategory) types2.EntryWithCategory {
+	rule := transform.rule
+	if rule.Rename != "" {
+		entry.Entry.DisplayName = rule.Rename
+	}
+	if rule.Category != "" {
+		entry.Category = rule.Category
+	}
+	if transform.target != 0 {
+		entry.Entry.Target = transform.target
+	}
+	if rule.Order != nil {
+		entry.Entry.Weight = *rule.Order
+	}
+	return entry
+}
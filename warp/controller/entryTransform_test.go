@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_applyEntryTransforms(t *testing.T) {
+	order := 10
+	redmine := getEntryWithCategory("Redmine", "/redmine", "Redmine", "Development Apps", types2.TARGET_SELF)
+	redmine.Tags = []string{"warp", "pm"}
+	jenkins := getEntryWithCategory("Jenkins", "/jenkins", "Jenkins", "Development Apps", types2.TARGET_SELF)
+	jenkins.Tags = []string{"warp", "ci"}
+	entries := func() []types2.EntryWithCategory {
+		return []types2.EntryWithCategory{redmine, jenkins}
+	}
+
+	t.Run("should return entries without rules", func(t *testing.T) {
+		// when
+		result := applyEntryTransforms(newEntryTransforms(nil), defaultHost, "dogus", entries())
+
+		// then
+		assert.Equal(t, entries(), result)
+	})
+
+	t.Run("should apply actions to matching entries", func(t *testing.T) {
+		// given
+		rules := []config.TransformRule{{
+			Match:    config.TransformMatch{Source: "dogus", Category: "Development Apps", Name: "^Jen", Tag: "ci"},
+			Rename:   "CI Server",
+			Category: "Development Apps/CI",
+			Target:   "newWindow",
+			Order:    &order,
+		}}
+
+		// when
+		result := applyEntryTransforms(newEntryTransforms(rules), defaultHost, "dogus", entries())
+
+		// then
+		require.Len(t, result, 2)
+		assert.Equal(t, redmine, result[0])
+		assert.Equal(t, "CI Server", result[1].Entry.DisplayName)
+		assert.Equal(t, "Development Apps/CI", result[1].Category)
+		assert.Equal(t, types2.TARGET_NEW_WINDOW, result[1].Entry.Target)
+		assert.Equal(t, 10, result[1].Entry.Weight)
+	})
+
+	t.Run("should hide matching entries", func(t *testing.T) {
+		// given
+		rules := []config.TransformRule{{Match: config.TransformMatch{Tag: "pm"}, Hide: true}}
+
+		// when
+		result := applyEntryTransforms(newEntryTransforms(rules), defaultHost, "dogus", entries())
+
+		// then
+		assert.Equal(t, []types2.EntryWithCategory{jenkins}, result)
+	})
+
+	t.Run("should not apply rule of other source", func(t *testing.T) {
+		// given
+		rules := []config.TransformRule{{Match: config.TransformMatch{Source: "externals"}, Hide: true}}
+
+		// when
+		result := applyEntryTransforms(newEntryTransforms(rules), defaultHost, "dogus", entries())
+
+		// then
+		assert.Equal(t, entries(), result)
+	})
+
+	t.Run("should apply rules in their order", func(t *testing.T) {
+		// given
+		rules := []config.TransformRule{
+			{Match: config.TransformMatch{Name: "Redmine"}, Category: "Project Management"},
+			{Match: config.TransformMatch{Category: "Project Management"}, Rename: "Issues"},
+		}
+
+		// when
+		result := applyEntryTransforms(newEntryTransforms(rules), defaultHost, "dogus", entries())
+
+		// then
+		assert.Equal(t, "Project Management", result[0].Category)
+		assert.Equal(t, "Issues", result[0].Entry.DisplayName)
+		assert.Equal(t, jenkins, result[1])
+	})
+
+	t.Run("should skip rule with invalid name pattern", func(t *testing.T) {
+		// given
+		rules := []config.TransformRule{{Match: config.TransformMatch{Name: "("}, Hide: true}}
+
+		// when
+		transforms := newEntryTransforms(rules)
+		result := applyEntryTransforms(transforms, defaultHost, "dogus", entries())
+
+		// then
+		assert.Empty(t, transforms)
+		assert.Equal(t, entries(), result)
+	})
+}
+
+func Test_matchesHost(t *testing.T) {
+	assert.True(t, matchesHost("partner.example.com", "partner.example.com"))
+	assert.True(t, matchesHost("Partner.example.com", "partner.example.com"))
+	assert.False(t, matchesHost("partner.example.com", defaultHost))
+	assert.False(t, matchesHost("!partner.example.com", "partner.example.com"))
+	assert.True(t, matchesHost("!partner.example.com", defaultHost))
+	assert.True(t, matchesHost("!partner.example.com", "ces.example.com"))
+}
+
+func TestConfigReader_Read_transforms(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"externals/cloudogu": "URL: https://www.cloudogu.com"})
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+	mockExternalConverter := NewMockExternalConverter(t)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("URL: https://www.cloudogu.com").
+		Return(types2.EntryWithCategory{Entry: types2.Entry{DisplayName: "Cloudogu", Href: "https://www.cloudogu.com", Target: types2.TARGET_EXTERNAL}, Category: "External"}, nil)
+	configuration := &config.Configuration{
+		Sources:    []config.Source{{Path: "externals", Type: "externals"}},
+		Transforms: []config.TransformRule{{Match: config.TransformMatch{Source: "externals"}, Category: "Links"}},
+	}
+	reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(&ExternalsSourceReader{externalConverter: mockExternalConverter}), nil)
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "Links", categories[0].Title)
+	assert.Equal(t, "Cloudogu", categories[0].Entries[0].DisplayName)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+)
+
+// isValidAt returns whether an entry with the given bounds is shown at the given time and the next time at which
+// this changes. Zero bounds are unbounded. The next change is zero if the entry is not affected by time anymore.
+func isValidAt(validFrom time.Time, validUntil time.Time, now time.Time) (bool, time.Time) {
+	if !validFrom.IsZero() && now.Before(validFrom) {
+		return false, validFrom
+	}
+	if !validUntil.IsZero() {
+		if !now.Before(validUntil) {
+			return false, time.Time{}
+		}
+		return true, validUntil
+	}
+	return true, time.Time{}
+}
+
+// filterValidEntries returns the entries shown at the given time and the next time at which one of the entries
+// appears or disappears.
+func filterValidEntries(entries []types2.EntryWithCategory, now time.Time) ([]types2.EntryWithCategory, time.Time) {
+	var nextChange time.Time
+	result := make([]types2.EntryWithCategory, 0, len(entries))
+	for _, entry := range entries {
+		valid, entryChange := isValidAt(entry.ValidFrom, entry.ValidUntil, now)
+		nextChange = earliestTime(nextChange, entryChange)
+		if valid {
+			result = append(result, entry)
+		}
+	}
+	return result, nextChange
+}
+
+// filterValidSupportSources returns the support entries shown at the given time and the next time at which one of
+// them appears or disappears.
+func filterValidSupportSources(sources []config.SupportSource, now time.Time) ([]config.SupportSource, time.Time) {
+	var nextChange time.Time
+	var result []config.SupportSource
+	for _, source := range sources {
+		valid, sourceChange := isValidAt(source.ValidFrom, source.ValidUntil, now)
+		nextChange = earliestTime(nextChange, sourceChange)
+		if valid {
+			result = append(result, source)
+		}
+	}
+	return result, nextChange
+}
+
+// earliestTime returns the earlier of both times, ignoring zero times.
+func earliestTime(a time.Time, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isValidAt(t *testing.T) {
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		validFrom      time.Time
+		validUntil     time.Time
+		wantValid      bool
+		wantNextChange time.Time
+	}{
+		{name: "unbounded", wantValid: true},
+		{name: "started", validFrom: past, wantValid: true},
+		{name: "not started", validFrom: future, wantValid: false, wantNextChange: future},
+		{name: "not ended", validUntil: future, wantValid: true, wantNextChange: future},
+		{name: "ended", validUntil: past, wantValid: false},
+		{name: "ends now", validUntil: now, wantValid: false},
+		{name: "starts now", validFrom: now, wantValid: true},
+		{name: "not started with end", validFrom: future, validUntil: future.Add(time.Hour), wantValid: false, wantNextChange: future},
+		{name: "started with end", validFrom: past, validUntil: future, wantValid: true, wantNextChange: future},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, nextChange := isValidAt(tt.validFrom, tt.validUntil, now)
+
+			assert.Equal(t, tt.wantValid, valid)
+			assert.Equal(t, tt.wantNextChange, nextChange)
+		})
+	}
+}
+
+func Test_filterValidEntries(t *testing.T) {
+	// given
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	permanent := getEntryWithCategory("Cloudogu", "https://cloudogu.com", "Cloudogu", "Links", types2.TARGET_EXTERNAL)
+	training := getEntryWithCategory("Training", "https://training.example.com", "Training", "Links", types2.TARGET_EXTERNAL)
+	training.ValidUntil = now.Add(48 * time.Hour)
+	migration := getEntryWithCategory("Migration", "https://migration.example.com", "Migration", "Links", types2.TARGET_EXTERNAL)
+	migration.ValidFrom = now.Add(24 * time.Hour)
+	expired := getEntryWithCategory("Expired", "https://expired.example.com", "Expired", "Links", types2.TARGET_EXTERNAL)
+	expired.ValidUntil = now.Add(-time.Hour)
+
+	// when
+	result, nextChange := filterValidEntries([]types2.EntryWithCategory{permanent, training, migration, expired}, now)
+
+	// then
+	assert.Equal(t, []types2.EntryWithCategory{permanent, training}, result)
+	assert.Equal(t, now.Add(24*time.Hour), nextChange)
+}
+
+func Test_filterValidSupportSources(t *testing.T) {
+	// given
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	sources := []config.SupportSource{
+		{Identifier: "docsCloudoguComUrl"},
+		{Identifier: "migrationGuide", ValidUntil: now.Add(time.Hour)},
+		{Identifier: "training", ValidFrom: now.Add(2 * time.Hour)},
+	}
+
+	// when
+	result, nextChange := filterValidSupportSources(sources, now)
+
+	// then
+	assert.Equal(t, sources[:2], result)
+	assert.Equal(t, now.Add(time.Hour), nextChange)
+}
+
+func Test_earliestTime(t *testing.T) {
+	early := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	assert.Equal(t, early, earliestTime(early, late))
+	assert.Equal(t, early, earliestTime(late, early))
+	assert.Equal(t, early, earliestTime(time.Time{}, early))
+	assert.Equal(t, early, earliestTime(early, time.Time{}))
+	assert.True(t, earliestTime(time.Time{}, time.Time{}).IsZero())
+}
+
+func TestConfigReader_Read_validity(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{
+		"externals/training": "training",
+		"externals/expired":  "expired",
+	})
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+	validUntil := time.Now().Add(time.Hour)
+	training := getEntryWithCategory("Training", "https://training.example.com", "Training", "Links", types2.TARGET_EXTERNAL)
+	training.ValidUntil = validUntil
+	expired := getEntryWithCategory("Expired", "https://expired.example.com", "Expired", "Links", types2.TARGET_EXTERNAL)
+	expired.ValidUntil = time.Now().Add(-time.Hour)
+	mockExternalConverter := NewMockExternalConverter(t)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("training").Return(training, nil)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("expired").Return(expired, nil)
+	supportValidFrom := time.Now().Add(2 * time.Hour)
+	configuration := &config.Configuration{
+		Sources: []config.Source{{Path: "externals", Type: "externals"}},
+		Support: []config.SupportSource{{Identifier: "training", Href: "https://training.example.com", ValidFrom: supportValidFrom}},
+	}
+	reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(&ExternalsSourceReader{externalConverter: mockExternalConverter}), nil)
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	require.Len(t, categories[0].Entries, 1)
+	assert.Equal(t, "Training", categories[0].Entries[0].DisplayName)
+	assert.Equal(t, validUntil, reader.NextEntryChange())
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// eventFilterPredicate accepts events of the warp config configmap. Changes of the dogus and the global config are
+// received from the registry watches.
+func eventFilterPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return isWatchedConfigMap(object.GetName())
+	})
+}
+
+func isWatchedConfigMap(configMapName string) bool {
+	return configMapName == config.WarpConfigMap
+}
+
+// relevantWatchTriggers returns the changes that affect the warp menu: the watch triggers of all sources and the
+// global config keys read for the support entries, the categories, the bookmark file and the templates.
+func relevantWatchTriggers(configuration *config.Configuration, sourceReaders *SourceReaderRegistry) SourceWatchTriggers {
+	triggers := sourceReaders.WatchTriggers(configuration.Sources)
+	triggers.GlobalConfigKeys = append([]string{
+		GlobalBlockWarpSupportCategoryConfigurationKey,
+		GlobalDisabledWarpSupportEntriesConfigurationKey,
+		GlobalAllowedWarpSupportEntriesConfigurationKey,
+		GlobalWarpCategoriesConfigurationKey,
+		GlobalWarpBasePathConfigurationKey,
+		// the fqdn is used for the links in the bookmark file
+		fqdnGlobalConfigKey,
+	}, triggers.GlobalConfigKeys...)
+	// hrefs and descriptions of entries can contain the template variables
+	triggers.GlobalConfigKeys = append(triggers.GlobalConfigKeys, domainGlobalConfigKey)
+	triggers.GlobalConfigKeys = append(triggers.GlobalConfigKeys, configuration.TemplateVariables...)
+	return triggers
+}
+
+func (r *WarpMenuConfigReconciler) setRelevantWatchTriggers(triggers SourceWatchTriggers) {
+	r.relevantTriggersMutex.Lock()
+	defer r.relevantTriggersMutex.Unlock()
+	r.relevantTriggers = &triggers
+}
+
+// relevantGlobalConfigKeys returns the global config keys that affect the warp menu or nil if the warp config was not
+// read yet.
+func (r *WarpMenuConfigReconciler) relevantGlobalConfigKeys() []string {
+	r.relevantTriggersMutex.Lock()
+	defer r.relevantTriggersMutex.Unlock()
+	if r.relevantTriggers == nil {
+		return nil
+	}
+	return r.relevantTriggers.GlobalConfigKeys
+}
+
+// doguVersionsRelevant returns whether changes of the installed dogus affect the warp menu. They are relevant until
+// the warp config was read.
+func (r *WarpMenuConfigReconciler) doguVersionsRelevant() bool {
+	r.relevantTriggersMutex.Lock()
+	defer r.relevantTriggersMutex.Unlock()
+	return r.relevantTriggers == nil || r.relevantTriggers.DoguVersions
+}
+
+// relevantGlobalConfigKeyFilter matches if a key starting with a relevant key changed, like the externals reader
+// matches its keys. The relevant keys are read for every change, because they depend on the warp config. All keys are
+// relevant if relevantKeys returns nil.
+func relevantGlobalConfigKeyFilter(relevantKeys func() []string) libconfig.WatchFilter {
+	return func(diffs []libconfig.DiffResult) bool {
+		keys := relevantKeys()
+		if keys == nil {
+			return len(diffs) > 0
+		}
+
+		return slices.ContainsFunc(diffs, func(diff libconfig.DiffResult) bool {
+			return slices.ContainsFunc(keys, func(relevantKey string) bool {
+				return strings.HasPrefix(diff.Key.String(), relevantKey)
+			})
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"testing"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelevantGlobalConfigKeyFilter(t *testing.T) {
+	relevantKeys := func() []string {
+		return relevantWatchTriggers(&config.Configuration{Sources: []config.Source{{Path: "externals", Type: "externals"}, {Path: "/dogu", Type: "dogus"}}}, testSourceReaders()).GlobalConfigKeys
+	}
+	diff := func(key string) []libconfig.DiffResult {
+		return []libconfig.DiffResult{{
+			Key:        libconfig.Key(key),
+			Value:      libconfig.OptionalValue{String: "old", Exists: true},
+			OtherValue: libconfig.OptionalValue{String: "new", Exists: true},
+		}}
+	}
+
+	t.Run("should not match unrelated global config keys", func(t *testing.T) {
+		assert.False(t, relevantGlobalConfigKeyFilter(relevantKeys)(diff("admin_group")))
+	})
+
+	t.Run("should match changed external", func(t *testing.T) {
+		assert.True(t, relevantGlobalConfigKeyFilter(relevantKeys)(diff("externals/cloudogu")))
+	})
+
+	t.Run("should match support and category keys", func(t *testing.T) {
+		assert.True(t, relevantGlobalConfigKeyFilter(relevantKeys)(diff("block_warpmenu_support_category")))
+		assert.True(t, relevantGlobalConfigKeyFilter(relevantKeys)(diff("warpmenu_categories")))
+		assert.True(t, relevantGlobalConfigKeyFilter(relevantKeys)(diff("fqdn")))
+	})
+
+	t.Run("should match if one of several keys is relevant", func(t *testing.T) {
+		diffs := append(diff("admin_group"), diff("externals/cloudogu")...)
+
+		assert.True(t, relevantGlobalConfigKeyFilter(relevantKeys)(diffs))
+	})
+
+	t.Run("should match every change before the warp config was read", func(t *testing.T) {
+		filter := relevantGlobalConfigKeyFilter(func() []string { return nil })
+
+		assert.True(t, filter(diff("admin_group")))
+		assert.False(t, filter(nil))
+	})
+}
+
+func testSourceReaders() *SourceReaderRegistry {
+	return NewSourceReaderRegistry(&DogusSourceReader{}, NewExternalsSourceReader())
+}
+
+func TestWarpMenuConfigReconciler_relevantWatchTriggers(t *testing.T) {
+	t.Run("should return all global config keys and dogus before the warp config was read", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{}
+
+		// when
+		keys := reconciler.relevantGlobalConfigKeys()
+		doguVersionsRelevant := reconciler.doguVersionsRelevant()
+
+		// then
+		assert.Nil(t, keys)
+		assert.True(t, doguVersionsRelevant)
+	})
+
+	t.Run("should return the watch triggers of the configured sources", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{}
+
+		// when
+		reconciler.setRelevantWatchTriggers(relevantWatchTriggers(&config.Configuration{Sources: []config.Source{{Path: "externals", Type: "externals"}}}, testSourceReaders()))
+
+		// then
+		assert.Equal(t, []string{
+			"block_warpmenu_support_category",
+			"disabled_warpmenu_support_entries",
+			"allowed_warpmenu_support_entries",
+			"warpmenu_categories",
+			"warpmenu_base_path",
+			"fqdn",
+			"externals",
+			"domain",
+		}, reconciler.relevantGlobalConfigKeys())
+		assert.False(t, reconciler.doguVersionsRelevant())
+	})
+
+	t.Run("should watch template variables", func(t *testing.T) {
+		// when
+		triggers := relevantWatchTriggers(&config.Configuration{TemplateVariables: []string{"certificate/type"}}, testSourceReaders())
+
+		// then
+		assert.Subset(t, triggers.GlobalConfigKeys, []string{"fqdn", "domain", "certificate/type"})
+	})
+
+	t.Run("should watch dogus if a dogus source is configured", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{}
+
+		// when
+		reconciler.setRelevantWatchTriggers(relevantWatchTriggers(&config.Configuration{Sources: []config.Source{{Path: "/dogu", Type: "dogus"}}}, testSourceReaders()))
+
+		// then
+		assert.True(t, reconciler.doguVersionsRelevant())
+	})
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const externalsSourceType = "externals"
+
+// ExternalsSourceReader reads external links from a directory of the global config.
+type ExternalsSourceReader struct {
+	externalConverter ExternalConverter
+}
+
+func NewExternalsSourceReader() *ExternalsSourceReader {
+	return &ExternalsSourceReader{externalConverter: &types2.ExternalConverter{}}
+}
+
+func (r *ExternalsSourceReader) Type() string {
+	return externalsSourceType
+}
+
+func (r *ExternalsSourceReader) Schema() SourceSchema {
+	return SourceSchema{RequiredFields: []string{sourceFieldPath}}
+}
+
+// RendersTemplates returns true, because external links are maintained per installation.
+func (r *ExternalsSourceReader) RendersTemplates() bool {
+	return true
+}
+
+// WatchTriggers returns the directory of the external links.
+func (r *ExternalsSourceReader) WatchTriggers(source config.Source) SourceWatchTriggers {
+	return SourceWatchTriggers{GlobalConfigKeys: []string{removeLegacyGlobalConfigPrefix(source.Path)}}
+}
+
+// Read converts all keys below the path of the source to entries. Keys that cannot be converted are rejected.
+// A global config that could not be read already fails the source, because the reader declares the path as global
+// config key.
+func (r *ExternalsSourceReader) Read(_ context.Context, source config.Source, globalConfig libconfig.GlobalConfig, _ error) (SourceResult, error) {
+	ctrl.Log.Info(fmt.Sprintf("Read externals from %s for warp menu in global config", source.Path))
+	children := readGlobalConfigDir(globalConfig, removeLegacyGlobalConfigPrefix(source.Path))
+	keys := make([]string, 0, len(children))
+	for key := range children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var externals []types2.EntryWithCategory
+	var rejectedEntries []RejectedEntry
+	for _, key := range keys {
+		external, unmarshalErr := r.externalConverter.ReadAndUnmarshalExternal(children[key])
+		if unmarshalErr != nil {
+			ctrl.Log.Error(unmarshalErr, fmt.Sprintf("failed to read and unmarshal external link key %q", key))
+			rejectedEntries = append(rejectedEntries, RejectedEntry{Key: key, Reason: unmarshalErr.Error()})
+			continue
+		}
+		external.Entry.ID = path.Base(key)
+		externals = append(externals, external)
+	}
+	return SourceResult{Entries: externals, RejectedEntries: rejectedEntries}, nil
+}
+
+func readGlobalConfigDir(globalConfig libconfig.GlobalConfig, key string) map[string]string {
+	entries := globalConfig.GetAll()
+	children := make(map[string]string, len(entries))
+	for entryKey, entryValue := range entries {
+		if strings.HasPrefix(entryKey.String(), key) && entryKey.String() != key {
+			children[entryKey.String()] = entryValue.String()
+		}
+	}
+
+	return children
+}
+
+func removeLegacyGlobalConfigPrefix(key string) string {
+	if strings.HasPrefix(key, "config/_global") || strings.HasPrefix(key, "/config/_global") {
+		_, after, _ := strings.Cut(key, "config/_global/")
+		return after
+	}
+
+	return key
+}
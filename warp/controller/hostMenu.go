@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// defaultHost is the host of the menu.json, which is served for all hosts without their own menu.
+	defaultHost        = ""
+	hostMenuFilePrefix = "menu."
+	hostMenuFileSuffix = ".json"
+)
+
+// hostNamePattern matches lowercase DNS names. It prevents host names from changing the path of their menu file.
+var hostNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// validHosts returns the configured host names in lowercase without duplicates. Invalid host names are logged and
+// skipped.
+func validHosts(hosts []string) []string {
+	var result []string
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if !isValidHost(host) {
+			ctrl.Log.Info(fmt.Sprintf("Invalid host %q in warp config, skipping host menu", host))
+			continue
+		}
+		if !slices.Contains(result, host) {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+func isValidHost(host string) bool {
+	// the file of the host would replace the last known good menu
+	return hostNamePattern.MatchString(host) && hostMenuFileName(host) != menuLastGoodFileName
+}
+
+func hostMenuFileName(host string) string {
+	return hostMenuFilePrefix + host + hostMenuFileSuffix
+}
+
+// writeHostMenuFiles writes a menu file for every host. nginx selects the file of the requested host with try_files.
+// Menu files of hosts that are no longer configured are removed.
+func (r *WarpMenuConfigReconciler) writeHostMenuFiles(hostCategories map[string]types.Categories, menuFormat string, compression config.CompressionConfig) error {
+	format, err := types.ParseMenuFormat(menuFormat)
+	if err != nil {
+		format = types.MenuFormatV1
+	}
+
+	hosts := make([]string, 0, len(hostCategories))
+	for host := range hostCategories {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+
+	for _, host := range hosts {
+		jsonData, err := r.marshalWarpMenu(hostMenuFileName(host), hostCategories[host], format)
+		if err != nil {
+			return fmt.Errorf("failed to marshal warp menu of host %s: %w", host, err)
+		}
+		if err = types.ValidateMenu(jsonData, format); err != nil {
+			return fmt.Errorf("generated warp menu of host %s is invalid: %w", host, err)
+		}
+		if err = r.writeCompressedFile(hostMenuFileName(host), jsonData, compression); err != nil {
+			return err
+		}
+	}
+
+	return r.removeStaleHostMenuFiles(hosts)
+}
+
+// removeStaleHostMenuFiles removes the menu files of hosts that are not configured anymore, so their requests are
+// served with the default menu again.
+func (r *WarpMenuConfigReconciler) removeStaleHostMenuFiles(hosts []string) error {
+	paths, err := filepath.Glob(filepath.Join(r.warpMenuPath, hostMenuFilePrefix+"*"+hostMenuFileSuffix))
+	if err != nil {
+		return fmt.Errorf("failed to list host warp menu files: %w", err)
+	}
+
+	var errs []error
+	for _, path := range paths {
+		host := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), hostMenuFilePrefix), hostMenuFileSuffix)
+		if !isValidHost(host) || slices.Contains(hosts, host) {
+			continue
+		}
+		if err = removeCompressedFile(path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove warp menu of host %s: %w", host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
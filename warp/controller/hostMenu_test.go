@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validHosts(t *testing.T) {
+	// when
+	hosts := validHosts([]string{"CES.example.com", "partner.example.com", "ces.example.com", "../etc", "last-good", "", "bad_host.example.com"})
+
+	// then
+	assert.Equal(t, []string{"ces.example.com", "partner.example.com"}, hosts)
+}
+
+func TestWarpMenuConfigReconciler_writeHostMenuFiles(t *testing.T) {
+	t.Run("should write menu files of the hosts", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		hostCategories := map[string]types.Categories{"partner.example.com": validCategories, "ces.example.com": validCategories}
+
+		// when
+		err := reconciler.writeHostMenuFiles(hostCategories, "", config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		menu, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.partner.example.com.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"Title":"Links","Order":0,"Entries":[{"DisplayName":"Docs","Href":"https://docs.cloudogu.com","Title":"","Target":"external"}]}]`, string(menu))
+		assert.FileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.ces.example.com.json"))
+	})
+
+	t.Run("should remove menu files of hosts that are not configured anymore", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		require.NoError(t, reconciler.writeHostMenuFiles(map[string]types.Categories{"partner.example.com": validCategories}, "", config.CompressionConfig{}))
+		require.NoError(t, reconciler.writeWarpMenuFile(validCategories, "", config.CompressionConfig{}))
+
+		// when
+		err := reconciler.writeHostMenuFiles(map[string]types.Categories{}, "", config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.partner.example.com.json"))
+		assert.NoFileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.partner.example.com.json.gz"))
+		assert.FileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.json"))
+		assert.FileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.json.gz"))
+		assert.FileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.last-good.json"))
+	})
+
+	t.Run("should fail for invalid menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		invalidCategories := types.Categories{{Title: "Support", Entries: types.Entries{{Title: "about", Target: types.TARGET_SELF}}}}
+		hostCategories := map[string]types.Categories{"partner.example.com": invalidCategories}
+
+		// when
+		err := reconciler.writeHostMenuFiles(hostCategories, "", config.CompressionConfig{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "generated warp menu of host partner.example.com is invalid")
+		assert.NoFileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.partner.example.com.json"))
+	})
+}
+
+func TestConfigReader_Read_hosts(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{
+		"externals/intranet": "intranet",
+		"externals/partner":  "partner",
+	})
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+	mockExternalConverter := NewMockExternalConverter(t)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("intranet").
+		Return(getEntryWithCategory("Intranet", "https://intranet.example.com", "Intranet", "Links", types.TARGET_EXTERNAL), nil)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("partner").
+		Return(getEntryWithCategory("Partner Portal", "https://partner.example.com/portal", "Partner Portal", "Links", types.TARGET_EXTERNAL), nil)
+	configuration := &config.Configuration{
+		Sources: []config.Source{{Path: "externals", Type: "externals"}},
+		Hosts:   []string{"Partner.example.com"},
+		Transforms: []config.TransformRule{
+			{Match: config.TransformMatch{Host: "!partner.example.com", Name: "^Partner"}, Hide: true},
+			{Match: config.TransformMatch{Host: "partner.example.com", Name: "^Intranet$"}, Hide: true},
+		},
+	}
+	reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(&ExternalsSourceReader{externalConverter: mockExternalConverter}), nil)
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	require.Len(t, categories[0].Entries, 1)
+	assert.Equal(t, "Intranet", categories[0].Entries[0].DisplayName)
+	// hidden entries are counted, so hiding them does not trigger the shrink guard
+	assert.Equal(t, map[string]int{"externals:externals": 2}, reader.EntryCounts())
+	partnerCategories := reader.HostCategories()["partner.example.com"]
+	require.Len(t, partnerCategories, 1)
+	require.Len(t, partnerCategories[0].Entries, 1)
+	assert.Equal(t, "Partner Portal", partnerCategories[0].Entries[0].DisplayName)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+)
+
+const (
+	domainGlobalConfigKey = "domain"
+	// supportTemplateSource is the source of template errors of support entries.
+	supportTemplateSource = "support"
+)
+
+// TemplateError describes a warp menu entry that was skipped because its href or description could not be rendered.
+type TemplateError struct {
+	// Source is the source of the entry, e.g. "externals:externals" or "support".
+	Source string `json:"source"`
+	Entry  string `json:"entry"`
+	Reason string `json:"reason"`
+}
+
+// templateVariableKeys returns the global config keys that can be used in templates: the fqdn, the domain and the
+// keys allowed in the warp config.
+func templateVariableKeys(configuration *config.Configuration) []string {
+	return append([]string{fqdnGlobalConfigKey, domainGlobalConfigKey}, configuration.TemplateVariables...)
+}
+
+// readTemplateVariables returns the values of the allowed global config keys. Keys that are not set are missing, so
+// templates using them fail instead of rendering an incomplete link.
+func readTemplateVariables(globalConfig libconfig.GlobalConfig, keys []string) map[string]string {
+	variables := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, exists := globalConfig.Get(libconfig.Key(key)); exists {
+			variables[key] = value.String()
+		}
+	}
+	return variables
+}
+
+// renderTemplate replaces the variables in the text, e.g. "https://{{ .fqdn }}/scm". Only the given variables are
+// available. Texts without template actions are returned unchanged.
+func renderTemplate(text string, variables map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %q: %w", text, err)
+	}
+
+	var result strings.Builder
+	if err = tmpl.Execute(&result, variables); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", text, err)
+	}
+	return result.String(), nil
+}
+
+// renderEntries renders the href and the description of the entries of a source whose reader renders templates, see
+// TemplateSourceReader. Entries that cannot be rendered are skipped and recorded as template errors.
+func (reader *ConfigReader) renderEntries(source config.Source, entries []types2.EntryWithCategory, variables map[string]string) []types2.EntryWithCategory {
+	if !reader.rendersTemplates(source) {
+		return entries
+	}
+
+	result := make([]types2.EntryWithCategory, 0, len(entries))
+	for _, entry := range entries {
+		href, err := renderTemplate(entry.Entry.Href, variables)
+		if err == nil {
+			entry.Entry.Href = href
+			entry.Entry.Title, err = renderTemplate(entry.Entry.Title, variables)
+		}
+		if err != nil {
+			reader.templateErrors = append(reader.templateErrors, TemplateError{Source: sourceKey(source), Entry: entryName(entry.Entry), Reason: err.Error()})
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (reader *ConfigReader) rendersTemplates(source config.Source) bool {
+	sourceReader, ok := reader.sourceReaders.Get(source.Type)
+	if !ok {
+		return false
+	}
+	templateReader, ok := sourceReader.(TemplateSourceReader)
+	return ok && templateReader.RendersTemplates()
+}
+
+// renderSupportSources renders the href of the support entries. Entries that cannot be rendered are skipped and
+// recorded as template errors.
+func (reader *ConfigReader) renderSupportSources(sources []config.SupportSource, variables map[string]string) []config.SupportSource {
+	var result []config.SupportSource
+	for _, source := range sources {
+		href, err := renderTemplate(source.Href, variables)
+		if err != nil {
+			reader.templateErrors = append(reader.templateErrors, TemplateError{Source: supportTemplateSource, Entry: source.Identifier, Reason: err.Error()})
+			continue
+		}
+		source.Href = href
+		result = append(result, source)
+	}
+	return result
+}
+
+func entryName(entry types2.Entry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	return entry.DisplayName
+}
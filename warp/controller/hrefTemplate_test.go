@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"testing"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	variables := map[string]string{"fqdn": "ces.example.com", "certificate/type": "selfsigned"}
+
+	t.Run("should return text without template unchanged", func(t *testing.T) {
+		// when
+		result, err := renderTemplate("https://docs.cloudogu.com", variables)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://docs.cloudogu.com", result)
+	})
+
+	t.Run("should render variables", func(t *testing.T) {
+		// when
+		result, err := renderTemplate(`https://{{ .fqdn }}/scm/help?cert={{ index . "certificate/type" }}`, variables)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://ces.example.com/scm/help?cert=selfsigned", result)
+	})
+
+	t.Run("should fail for missing variable", func(t *testing.T) {
+		// when
+		_, err := renderTemplate("https://{{ .admin_group }}/", variables)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to render template \"https://{{ .admin_group }}/\"")
+	})
+
+	t.Run("should fail for invalid template", func(t *testing.T) {
+		// when
+		_, err := renderTemplate("https://{{ .fqdn", variables)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse template")
+	})
+}
+
+func Test_readTemplateVariables(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{"fqdn": "ces.example.com", "admin_group": "admins", "password-policy/min_length": "14"})
+	configuration := &config.Configuration{TemplateVariables: []string{"password-policy/min_length"}}
+
+	// when
+	variables := readTemplateVariables(globalConfig, templateVariableKeys(configuration))
+
+	// then
+	assert.Equal(t, map[string]string{"fqdn": "ces.example.com", "password-policy/min_length": "14"}, variables)
+}
+
+func TestConfigReader_Read_templates(t *testing.T) {
+	// given
+	globalConfig := registryconfig.CreateGlobalConfig(registryconfig.Entries{
+		"fqdn":              "ces.example.com",
+		"domain":            "example.com",
+		"externals/scm":     "scm",
+		"externals/invalid": "invalid",
+	})
+	globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+	globalConfigRepoMock.EXPECT().Get(testCtx).Return(globalConfig, nil)
+	scm := getEntryWithCategory("SCM Help", "https://{{ .fqdn }}/scm/help", "Help for {{ .domain }}", "Help", types2.TARGET_EXTERNAL)
+	scm.Entry.ID = "scm"
+	invalid := getEntryWithCategory("Invalid", "https://{{ .unknown }}/", "Invalid", "Help", types2.TARGET_EXTERNAL)
+	invalid.Entry.ID = "invalid"
+	mockExternalConverter := NewMockExternalConverter(t)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("scm").Return(scm, nil)
+	mockExternalConverter.EXPECT().ReadAndUnmarshalExternal("invalid").Return(invalid, nil)
+	configuration := &config.Configuration{
+		Sources: []config.Source{{Path: "externals", Type: "externals"}},
+		Support: []config.SupportSource{{Identifier: "myCloudogu", External: true, Href: "https://{{ .domain }}/support"}},
+	}
+	reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(&ExternalsSourceReader{externalConverter: mockExternalConverter}), nil)
+
+	// when
+	categories, err := reader.Read(testCtx, configuration)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Help", categories[0].Title)
+	require.Len(t, categories[0].Entries, 1)
+	assert.Equal(t, "https://ces.example.com/scm/help", categories[0].Entries[0].Href)
+	assert.Equal(t, "Help for example.com", categories[0].Entries[0].Title)
+	assert.Equal(t, "Support", categories[1].Title)
+	assert.Equal(t, "https://example.com/support", categories[1].Entries[0].Href)
+	templateErrors := reader.TemplateErrors()
+	require.Len(t, templateErrors, 1)
+	assert.Equal(t, "externals:externals", templateErrors[0].Source)
+	assert.Equal(t, "invalid", templateErrors[0].Entry)
+	assert.Contains(t, templateErrors[0].Reason, "map has no entry for key \"unknown\"")
+}
+
+func TestConfigReader_renderEntries(t *testing.T) {
+	variables := map[string]string{"fqdn": "ces.example.com"}
+	status := getEntryWithCategory("Status", "https://{{ .fqdn }}/status", "Status of {{ .fqdn }}", "Links", types2.TARGET_EXTERNAL)
+
+	t.Run("should render entries of template sources", func(t *testing.T) {
+		// given
+		reader := &ConfigReader{sourceReaders: NewSourceReaderRegistry(&testSourceReader{sourceType: "static", templates: true})}
+
+		// when
+		result := reader.renderEntries(config.Source{Type: "static"}, []types2.EntryWithCategory{status}, variables)
+
+		// then
+		require.Len(t, result, 1)
+		assert.Equal(t, "https://ces.example.com/status", result[0].Entry.Href)
+		assert.Equal(t, "Status of ces.example.com", result[0].Entry.Title)
+	})
+
+	t.Run("should keep entries of other sources unchanged", func(t *testing.T) {
+		// given
+		reader := &ConfigReader{sourceReaders: NewSourceReaderRegistry(&DogusSourceReader{})}
+		redmine := getEntryWithCategory("Redmine", "/redmine", "Use {{ .project }} for projects", "Development Apps", types2.TARGET_SELF)
+
+		// when
+		result := reader.renderEntries(config.Source{Type: "dogus"}, []types2.EntryWithCategory{redmine}, variables)
+
+		// then
+		assert.Equal(t, []types2.EntryWithCategory{redmine}, result)
+		assert.Empty(t, reader.TemplateErrors())
+	})
+}
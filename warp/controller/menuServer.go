@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// menuServerMenuPath serves the warp menu of the requested host, like the menu.json served by nginx.
+	menuServerMenuPath = "/warp/menu/menu.json"
+	// menuServerEventsPath streams an event whenever the warp menu of the requested host changes.
+	menuServerEventsPath = "/warp/menu/events"
+	// menuEventName is the name of the server-sent event; its data is the ETag of the new menu.
+	menuEventName = "menu"
+	// menuEventRetry tells the browser after how many milliseconds it reconnects to the event stream.
+	menuEventRetry           = 5000
+	defaultKeepAliveInterval = 30 * time.Second
+	menuServerShutdownDelay  = 5 * time.Second
+)
+
+// MenuServer serves the current warp menu over HTTP with strong ETags and notifies open pages about changes with
+// server-sent events. The reconciler publishes every generated menu.
+type MenuServer struct {
+	address           string
+	keepAliveInterval time.Duration
+	mutex             sync.RWMutex
+	// menus contains the served menus by host. The menu of the defaultHost is served to all other hosts.
+	menus       map[string]servedMenu
+	subscribers map[chan struct{}]struct{}
+}
+
+type servedMenu struct {
+	data []byte
+	etag string
+}
+
+// NewMenuServer creates a server that listens on the given address, e.g. "127.0.0.1:8084", after it was added to the manager.
+func NewMenuServer(address string) *MenuServer {
+	return &MenuServer{
+		address:           address,
+		keepAliveInterval: defaultKeepAliveInterval,
+		menus:             map[string]servedMenu{},
+		subscribers:       map[chan struct{}]struct{}{},
+	}
+}
+
+// Publish replaces the served menus by host and notifies the subscribers if a menu changed.
+func (s *MenuServer) Publish(menus map[string][]byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	changed := len(menus) != len(s.menus)
+	served := make(map[string]servedMenu, len(menus))
+	for host, data := range menus {
+		menu := servedMenu{data: data, etag: computeETag(data)}
+		if s.menus[host].etag != menu.etag {
+			changed = true
+		}
+		served[host] = menu
+	}
+	s.menus = served
+	if !changed {
+		return
+	}
+
+	for subscriber := range s.subscribers {
+		// the channel is buffered, a pending notification already covers this change
+		select {
+		case subscriber <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start serves the menu until the context is done.
+func (s *MenuServer) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.address,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		// the request contexts end with the manager, so open event streams do not block the shutdown
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		ctrl.Log.Info(fmt.Sprintf("serving warp menu on %s", s.address))
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("failed to serve warp menu: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), menuServerShutdownDelay)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to stop warp menu server: %w", err)
+		}
+		return nil
+	}
+}
+
+func (s *MenuServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch r.URL.Path {
+	case menuServerMenuPath:
+		s.serveMenu(w, r)
+	case menuServerEventsPath:
+		s.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *MenuServer) serveMenu(w http.ResponseWriter, r *http.Request) {
+	menu, ok := s.menu(requestHost(r))
+	if !ok {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "warp menu was not generated yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	// browsers may cache the menu but have to revalidate it, which is answered with 304 as long as it is unchanged
+	w.Header().Set("ETag", menu.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), menu.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprint(len(menu.data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(menu.data)
+}
+
+// serveEvents streams the ETag of the menu of the requested host. An event is sent after connecting and whenever the
+// menu changes, so warp.js can fetch the new menu without reloading the page.
+func (s *MenuServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	subscriber := s.subscribe()
+	defer s.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// disables the response buffering of nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "retry: %d\n\n", menuEventRetry)
+
+	host := requestHost(r)
+	// a reconnecting browser sends the ETag it already knows
+	lastETag := r.Header.Get("Last-Event-ID")
+	keepAlive := time.NewTicker(s.keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		if menu, exists := s.menu(host); exists && menu.etag != lastETag {
+			lastETag = menu.etag
+			if _, err := fmt.Fprintf(w, "event: %s\nid: %s\ndata: %s\n\n", menuEventName, menu.etag, menu.etag); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-subscriber:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *MenuServer) menu(host string) (servedMenu, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if menu, ok := s.menus[host]; ok {
+		return menu, true
+	}
+	menu, ok := s.menus[defaultHost]
+	return menu, ok
+}
+
+func (s *MenuServer) subscribe() chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subscriber := make(chan struct{}, 1)
+	s.subscribers[subscriber] = struct{}{}
+	return subscriber
+}
+
+func (s *MenuServer) unsubscribe(subscriber chan struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.subscribers, subscriber)
+}
+
+// publishMenus publishes the written menu files, so the menu server serves the same menus as nginx.
+func (r *WarpMenuConfigReconciler) publishMenus(hostCategories map[string]types.Categories) error {
+	menus := make(map[string][]byte, len(hostCategories)+1)
+	fileNames := map[string]string{defaultHost: menuJsonFileName}
+	for host := range hostCategories {
+		fileNames[host] = hostMenuFileName(host)
+	}
+	for host, fileName := range fileNames {
+		data, err := os.ReadFile(filepath.Join(r.warpMenuPath, fileName))
+		if err != nil {
+			return fmt.Errorf("failed to read warp menu %s: %w", fileName, err)
+		}
+		menus[host] = data
+	}
+
+	r.menuServer.Publish(menus)
+	return nil
+}
+
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if hostName, _, err := net.SplitHostPort(host); err == nil {
+		host = hostName
+	}
+	return strings.ToLower(host)
+}
+
+// computeETag returns a strong ETag of the content.
+func computeETag(data []byte) string {
+	hash := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(hash[:]) + `"`
+}
+
+// etagMatches implements the weak comparison of If-None-Match, which is used for GET and HEAD requests.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
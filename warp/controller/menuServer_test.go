@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMenuServer_serveMenu(t *testing.T) {
+	t.Run("should return 503 before the first menu was published", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(NewMenuServer(""))
+		defer server.Close()
+
+		// when
+		resp, err := http.Get(server.URL + "/warp/menu/menu.json")
+
+		// then
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("should serve menu with strong etag and answer If-None-Match with 304", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		menuServer.Publish(map[string][]byte{defaultHost: []byte(`[]`)})
+		server := httptest.NewServer(menuServer)
+		defer server.Close()
+
+		// when
+		resp, err := http.Get(server.URL + "/warp/menu/menu.json")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		request, err := http.NewRequest(http.MethodGet, server.URL+"/warp/menu/menu.json", nil)
+		require.NoError(t, err)
+		request.Header.Set("If-None-Match", `"other", `+resp.Header.Get("ETag"))
+		cachedResp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer cachedResp.Body.Close()
+
+		// then
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, computeETag([]byte(`[]`)), resp.Header.Get("ETag"))
+		assert.False(t, strings.HasPrefix(resp.Header.Get("ETag"), "W/"))
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, http.StatusNotModified, cachedResp.StatusCode)
+	})
+
+	t.Run("should serve menu of the requested host", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		menuServer.Publish(map[string][]byte{defaultHost: []byte(`[]`), "partner.example.com": []byte(`[{"Title":"Partner"}]`)})
+
+		// when
+		partnerResp := httptest.NewRecorder()
+		menuServer.ServeHTTP(partnerResp, httptest.NewRequest(http.MethodGet, "http://Partner.example.com:443/warp/menu/menu.json", nil))
+		defaultResp := httptest.NewRecorder()
+		menuServer.ServeHTTP(defaultResp, httptest.NewRequest(http.MethodGet, "http://ces.example.com/warp/menu/menu.json", nil))
+
+		// then
+		assert.Equal(t, `[{"Title":"Partner"}]`, partnerResp.Body.String())
+		assert.Equal(t, `[]`, defaultResp.Body.String())
+	})
+
+	t.Run("should reject other methods", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		resp := httptest.NewRecorder()
+
+		// when
+		menuServer.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/warp/menu/menu.json", nil))
+
+		// then
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
+
+func TestMenuServer_serveEvents(t *testing.T) {
+	t.Run("should send etag after connecting and on change", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		menuServer.Publish(map[string][]byte{defaultHost: []byte(`[]`)})
+		server := httptest.NewServer(menuServer)
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/warp/menu/events", nil)
+		require.NoError(t, err)
+
+		// when
+		resp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		events := bufio.NewReader(resp.Body)
+		firstEvent := readEvent(t, events)
+		menuServer.Publish(map[string][]byte{defaultHost: []byte(`[{"Title":"Links"}]`)})
+		secondEvent := readEvent(t, events)
+
+		// then
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "event: menu\nid: "+computeETag([]byte(`[]`))+"\ndata: "+computeETag([]byte(`[]`))+"\n", firstEvent)
+		assert.Equal(t, "event: menu\nid: "+computeETag([]byte(`[{"Title":"Links"}]`))+"\ndata: "+computeETag([]byte(`[{"Title":"Links"}]`))+"\n", secondEvent)
+	})
+
+	t.Run("should not send known etag again after reconnect", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		menuServer.keepAliveInterval = 10 * time.Millisecond
+		menuServer.Publish(map[string][]byte{defaultHost: []byte(`[]`)})
+		server := httptest.NewServer(menuServer)
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/warp/menu/events", nil)
+		require.NoError(t, err)
+		request.Header.Set("Last-Event-ID", computeETag([]byte(`[]`)))
+
+		// when
+		resp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		event := readEvent(t, bufio.NewReader(resp.Body))
+
+		// then
+		assert.Equal(t, ": keep-alive\n", event)
+	})
+}
+
+// readEvent reads the next event of the stream without the retry field.
+func readEvent(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	var event strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			if event.Len() > 0 {
+				return event.String()
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "retry:") {
+			event.WriteString(line)
+		}
+	}
+}
+
+func TestWarpMenuConfigReconciler_publishMenus(t *testing.T) {
+	t.Run("should publish written menus", func(t *testing.T) {
+		// given
+		menuServer := NewMenuServer("")
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir(), menuServer: menuServer}
+		require.NoError(t, os.WriteFile(filepath.Join(reconciler.warpMenuPath, "menu.json"), []byte(`[]`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(reconciler.warpMenuPath, "menu.partner.example.com.json"), []byte(`[{}]`), 0644))
+
+		// when
+		err := reconciler.publishMenus(map[string]types.Categories{"partner.example.com": validCategories})
+
+		// then
+		require.NoError(t, err)
+		menu, ok := menuServer.menu("partner.example.com")
+		require.True(t, ok)
+		assert.Equal(t, `[{}]`, string(menu.data))
+		menu, ok = menuServer.menu("ces.example.com")
+		require.True(t, ok)
+		assert.Equal(t, `[]`, string(menu.data))
+	})
+
+	t.Run("should fail if menu was not written", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir(), menuServer: NewMenuServer("")}
+
+		// when
+		err := reconciler.publishMenus(nil)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read warp menu menu.json")
+	})
+}
+
+func TestMenuServer_Start(t *testing.T) {
+	// given
+	menuServer := NewMenuServer("127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// when
+	errChan := make(chan error, 1)
+	go func() { errChan <- menuServer.Start(ctx) }()
+	cancel()
+
+	// then
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("menu server did not stop")
+	}
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// registryWatchRetryInterval is the time to wait before a failed or ended registry watch is started again.
+const registryWatchRetryInterval = 5 * time.Second
+
+// registryWatcher forwards changes of the current dogu versions and the relevant global config keys as generic events
+// to the controller. It is added to the manager as runnable and restarts the watches until the manager stops.
+type registryWatcher struct {
+	namespace            string
+	doguVersionRegistry  DoguVersionRegistry
+	globalConfigRepo     GlobalConfigRepository
+	relevantKeys         func() []string
+	doguVersionsRelevant func() bool
+	events               chan event.GenericEvent
+	retryInterval        time.Duration
+}
+
+func newRegistryWatcher(namespace string, doguVersionRegistry DoguVersionRegistry, globalConfigRepo GlobalConfigRepository, relevantKeys func() []string, doguVersionsRelevant func() bool) *registryWatcher {
+	return &registryWatcher{
+		namespace:            namespace,
+		doguVersionRegistry:  doguVersionRegistry,
+		globalConfigRepo:     globalConfigRepo,
+		relevantKeys:         relevantKeys,
+		doguVersionsRelevant: doguVersionsRelevant,
+		events:               make(chan event.GenericEvent),
+		retryInterval:        registryWatchRetryInterval,
+	}
+}
+
+// Start runs the watches until the context is cancelled.
+func (w *registryWatcher) Start(ctx context.Context) error {
+	go w.keepWatching(ctx, "dogu versions", w.watchDoguVersions)
+	go w.keepWatching(ctx, "global config", w.watchGlobalConfig)
+
+	<-ctx.Done()
+	return nil
+}
+
+func (w *registryWatcher) keepWatching(ctx context.Context, name string, watch func(context.Context) error) {
+	logger := log.FromContext(ctx).WithName("registryWatcher").WithValues("watch", name)
+	for {
+		err := watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Error(err, "registry watch failed, restarting")
+		} else {
+			logger.Info("registry watch ended, restarting")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryInterval):
+		}
+
+		// changes may have been missed while the watch was not running
+		w.notify(ctx)
+	}
+}
+
+func (w *registryWatcher) watchDoguVersions(ctx context.Context) error {
+	results, err := w.doguVersionRegistry.WatchAllCurrent(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to watch current dogu versions: %w", err)
+	}
+
+	for result := range results {
+		if result.Err != nil {
+			log.FromContext(ctx).Error(result.Err, "error in dogu version watch")
+			continue
+		}
+		if w.doguVersionsRelevant() {
+			w.notify(ctx)
+		}
+	}
+	return nil
+}
+
+func (w *registryWatcher) watchGlobalConfig(ctx context.Context) error {
+	results, err := w.globalConfigRepo.Watch(ctx, relevantGlobalConfigKeyFilter(w.relevantKeys))
+	if err != nil {
+		return fmt.Errorf("failed to watch global config: %w", err)
+	}
+
+	for result := range results {
+		if result.Err != nil {
+			log.FromContext(ctx).Error(result.Err, "error in global config watch")
+			continue
+		}
+		w.notify(ctx)
+	}
+	return nil
+}
+
+// notify sends an event for the namespace of the watcher. The object carries only the namespace, because the event
+// handler maps all events of a namespace to the same request.
+func (w *registryWatcher) notify(ctx context.Context) {
+	object := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: w.namespace}}
+	select {
+	case w.events <- event.GenericEvent{Object: object}:
+	case <-ctx.Done():
+	}
+}
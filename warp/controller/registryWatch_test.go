@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/ces-commons-lib/dogu"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func receiveEvent(t *testing.T, events <-chan event.GenericEvent) event.GenericEvent {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no event received")
+		return event.GenericEvent{}
+	}
+}
+
+func TestRegistryWatcher_Start(t *testing.T) {
+	t.Run("should send event for changed dogu versions and global config", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doguResults := make(chan dogu.CurrentVersionsWatchResult)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(doguResults, nil)
+		globalConfigResults := make(chan repository.GlobalConfigWatchResult)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(globalConfigResults, nil)
+
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return true })
+		go func() { _ = watcher.Start(ctx) }()
+
+		// when
+		doguResults <- dogu.CurrentVersionsWatchResult{}
+		doguEvent := receiveEvent(t, watcher.events)
+		globalConfigResults <- repository.GlobalConfigWatchResult{}
+		globalConfigEvent := receiveEvent(t, watcher.events)
+
+		// then
+		assert.Equal(t, testNamespace, doguEvent.Object.GetNamespace())
+		assert.Equal(t, testNamespace, globalConfigEvent.Object.GetNamespace())
+	})
+
+	t.Run("should ignore errors in watch results", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doguResults := make(chan dogu.CurrentVersionsWatchResult)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(doguResults, nil)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(make(chan repository.GlobalConfigWatchResult), nil)
+
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return true })
+		go func() { _ = watcher.Start(ctx) }()
+
+		// when
+		doguResults <- dogu.CurrentVersionsWatchResult{Err: assert.AnError}
+		doguResults <- dogu.CurrentVersionsWatchResult{}
+
+		// then
+		receiveEvent(t, watcher.events)
+		select {
+		case <-watcher.events:
+			assert.Fail(t, "only one event expected")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should restart failed watch and send event", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(nil, errors.New("watch error")).Once()
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(make(chan dogu.CurrentVersionsWatchResult), nil)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(make(chan repository.GlobalConfigWatchResult), nil)
+
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return true })
+		watcher.retryInterval = time.Millisecond
+
+		// when
+		go func() { _ = watcher.Start(ctx) }()
+
+		// then
+		receiveEvent(t, watcher.events)
+	})
+
+	t.Run("should restart ended watch", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(make(chan dogu.CurrentVersionsWatchResult), nil)
+		closedResults := make(chan repository.GlobalConfigWatchResult)
+		close(closedResults)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(closedResults, nil).Once()
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(make(chan repository.GlobalConfigWatchResult), nil)
+
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return true })
+		watcher.retryInterval = time.Millisecond
+
+		// when
+		go func() { _ = watcher.Start(ctx) }()
+
+		// then
+		receiveEvent(t, watcher.events)
+	})
+
+	t.Run("should ignore dogu changes if no source reads the dogus", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		doguResults := make(chan dogu.CurrentVersionsWatchResult)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(doguResults, nil)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(make(chan repository.GlobalConfigWatchResult), nil)
+
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return false })
+		go func() { _ = watcher.Start(ctx) }()
+
+		// when
+		doguResults <- dogu.CurrentVersionsWatchResult{}
+
+		// then
+		select {
+		case <-watcher.events:
+			assert.Fail(t, "no event expected")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should stop if context is cancelled", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.Background())
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		doguVersionRegistryMock.EXPECT().WatchAllCurrent(mock.Anything).Return(make(chan dogu.CurrentVersionsWatchResult), nil).Maybe()
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Watch(mock.Anything, mock.Anything).Return(make(chan repository.GlobalConfigWatchResult), nil).Maybe()
+		watcher := newRegistryWatcher(testNamespace, doguVersionRegistryMock, globalConfigRepoMock, func() []string { return nil }, func() bool { return true })
+
+		// when
+		cancel()
+		err := watcher.Start(ctx)
+
+		// then
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const defaultMaxShrinkPercent = 50
+
+// checkShrinkGuard compares the entry counts of the new generation with the counts of the last written warp menu. An
+// error is returned if too many entries disappeared, e.g. because a source could temporarily not be read. A shrink
+// that persists for the grace period is accepted, because the entries were most likely removed on purpose.
+func (r *WarpMenuConfigReconciler) checkShrinkGuard(ctx context.Context, namespace string, guard config.ShrinkGuardConfig, entryCounts map[string]int) error {
+	if guard.Disabled {
+		return nil
+	}
+
+	previousStatus, err := r.readStatus(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	shrinkErr := checkShrink(previousStatus.EntryCounts, entryCounts, guard.MaxShrinkPercent)
+	if shrinkErr == nil {
+		delete(r.shrinkDetectedAt, namespace)
+		return nil
+	}
+
+	if r.shrinkDetectedAt == nil {
+		r.shrinkDetectedAt = map[string]time.Time{}
+	}
+	detectedAt, ok := r.shrinkDetectedAt[namespace]
+	if !ok {
+		detectedAt = time.Now()
+		r.shrinkDetectedAt[namespace] = detectedAt
+	}
+	gracePeriod := shrinkGracePeriod(guard)
+	if time.Since(detectedAt) >= gracePeriod {
+		delete(r.shrinkDetectedAt, namespace)
+		ctrl.Log.Info(fmt.Sprintf("Accepting shrink of the warp menu that persisted for %s: %s", gracePeriod, shrinkErr.Error()))
+		return nil
+	}
+
+	return fmt.Errorf("%w; accepted if it persists until %s", shrinkErr, detectedAt.Add(gracePeriod).Format(time.RFC3339))
+}
+
+func shrinkGracePeriod(guard config.ShrinkGuardConfig) time.Duration {
+	if guard.GracePeriod == "" {
+		return config.DefaultShrinkGracePeriod
+	}
+
+	gracePeriod, err := time.ParseDuration(guard.GracePeriod)
+	if err != nil || gracePeriod < 0 {
+		ctrl.Log.Info(fmt.Sprintf("Invalid shrink guard grace period %q, using default %s", guard.GracePeriod, config.DefaultShrinkGracePeriod))
+		return config.DefaultShrinkGracePeriod
+	}
+	return gracePeriod
+}
+
+func checkShrink(previousCounts map[string]int, currentCounts map[string]int, maxShrinkPercent int) error {
+	if maxShrinkPercent <= 0 {
+		maxShrinkPercent = defaultMaxShrinkPercent
+	}
+
+	previousTotal, currentTotal := 0, 0
+	var sources []string
+	for source, currentCount := range currentCounts {
+		// sources that were removed from the configuration or are new are not compared
+		previousCount, ok := previousCounts[source]
+		if !ok {
+			continue
+		}
+		sources = append(sources, source)
+		previousTotal += previousCount
+		currentTotal += currentCount
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		if previousCounts[source] > 0 && currentCounts[source] == 0 {
+			return fmt.Errorf("source %q contained %d entries before and is empty now", source, previousCounts[source])
+		}
+	}
+
+	if previousTotal > 0 && (previousTotal-currentTotal)*100 > maxShrinkPercent*previousTotal {
+		return fmt.Errorf("number of entries dropped from %d to %d, which is more than %d%%", previousTotal, currentTotal, maxShrinkPercent)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	types2 "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_checkShrink(t *testing.T) {
+	t.Run("should accept first generation", func(t *testing.T) {
+		assert.NoError(t, checkShrink(nil, map[string]int{"dogus:/dogu": 0}, 50))
+	})
+
+	t.Run("should accept small shrink", func(t *testing.T) {
+		assert.NoError(t, checkShrink(map[string]int{"dogus:/dogu": 10, "externals:externals": 2}, map[string]int{"dogus:/dogu": 8, "externals:externals": 2}, 50))
+	})
+
+	t.Run("should reject shrink above the configured percentage", func(t *testing.T) {
+		err := checkShrink(map[string]int{"dogus:/dogu": 10}, map[string]int{"dogus:/dogu": 7}, 20)
+
+		assert.EqualError(t, err, "number of entries dropped from 10 to 7, which is more than 20%")
+	})
+
+	t.Run("should use default percentage", func(t *testing.T) {
+		assert.NoError(t, checkShrink(map[string]int{"dogus:/dogu": 10}, map[string]int{"dogus:/dogu": 5}, 0))
+		assert.Error(t, checkShrink(map[string]int{"dogus:/dogu": 10}, map[string]int{"dogus:/dogu": 4}, 0))
+	})
+
+	t.Run("should reject source that is empty now", func(t *testing.T) {
+		err := checkShrink(map[string]int{"dogus:/dogu": 10, "externals:externals": 1}, map[string]int{"dogus:/dogu": 10, "externals:externals": 0}, 50)
+
+		assert.EqualError(t, err, "source \"externals:externals\" contained 1 entries before and is empty now")
+	})
+
+	t.Run("should ignore removed and new sources", func(t *testing.T) {
+		assert.NoError(t, checkShrink(map[string]int{"externals:externals": 10}, map[string]int{"externals:links": 0}, 50))
+	})
+}
+
+func Test_shrinkGracePeriod(t *testing.T) {
+	assert.Equal(t, config.DefaultShrinkGracePeriod, shrinkGracePeriod(config.ShrinkGuardConfig{}))
+	assert.Equal(t, time.Hour, shrinkGracePeriod(config.ShrinkGuardConfig{GracePeriod: "1h"}))
+	assert.Equal(t, time.Duration(0), shrinkGracePeriod(config.ShrinkGuardConfig{GracePeriod: "0s"}))
+	assert.Equal(t, config.DefaultShrinkGracePeriod, shrinkGracePeriod(config.ShrinkGuardConfig{GracePeriod: "soon"}))
+}
+
+func TestWarpMenuConfigReconciler_checkShrinkGuard(t *testing.T) {
+	statusKey := types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}
+
+	t.Run("should skip disabled guard", func(t *testing.T) {
+		reconciler := &WarpMenuConfigReconciler{client: newMockK8sClient(t)}
+
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{Disabled: true}, map[string]int{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept missing status", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpStatusConfigMap))
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{}, map[string]int{"dogus:/dogu": 0})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should ignore invalid status", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{invalid`}
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{}, map[string]int{"dogus:/dogu": 0})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject shrink against previous status", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"dogus:/dogu":10}}`}
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{MaxShrinkPercent: 10}, map[string]int{"dogus:/dogu": 8})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "number of entries dropped from 10 to 8")
+	})
+
+	t.Run("should accept shrink that persisted for the grace period", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"dogus:/dogu":10}}`}
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+		guard := config.ShrinkGuardConfig{GracePeriod: "30m"}
+
+		firstErr := reconciler.checkShrinkGuard(testCtx, testNamespace, guard, map[string]int{"dogus:/dogu": 0})
+		stillWithinGracePeriodErr := reconciler.checkShrinkGuard(testCtx, testNamespace, guard, map[string]int{"dogus:/dogu": 0})
+		reconciler.shrinkDetectedAt[testNamespace] = time.Now().Add(-31 * time.Minute)
+		acceptedErr := reconciler.checkShrinkGuard(testCtx, testNamespace, guard, map[string]int{"dogus:/dogu": 0})
+
+		require.Error(t, firstErr)
+		assert.ErrorContains(t, firstErr, "accepted if it persists until")
+		require.Error(t, stillWithinGracePeriodErr)
+		require.NoError(t, acceptedErr)
+		assert.NotContains(t, reconciler.shrinkDetectedAt, testNamespace)
+	})
+
+	t.Run("should reset grace period if the shrink disappears", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"dogus:/dogu":10}}`}
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		require.Error(t, reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{}, map[string]int{"dogus:/dogu": 0}))
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{}, map[string]int{"dogus:/dogu": 10})
+
+		require.NoError(t, err)
+		assert.NotContains(t, reconciler.shrinkDetectedAt, testNamespace)
+	})
+
+	t.Run("should fail to read status", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).Return(assert.AnError)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		err := reconciler.checkShrinkGuard(testCtx, testNamespace, config.ShrinkGuardConfig{}, map[string]int{})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
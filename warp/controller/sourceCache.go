@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	types3 "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const sourceCacheDataKey = "sources.json"
+
+// StaleSource describes a source that could not be read and whose last successful result is used instead.
+type StaleSource struct {
+	Source      string    `json:"source"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	Reason      string    `json:"reason"`
+}
+
+// cachedSource is the last successful result of a source.
+type cachedSource struct {
+	ReadAt  time.Time     `json:"readAt"`
+	Entries []cachedEntry `json:"entries"`
+}
+
+// cachedEntry contains all fields of an entry including those that are not part of the menu.json.
+type cachedEntry struct {
+	Category    string             `json:"category"`
+	DisplayName string             `json:"displayName"`
+	Href        string             `json:"href"`
+	Title       string             `json:"title"`
+	Target      types2.Target      `json:"target"`
+	ID          string             `json:"id,omitempty"`
+	Source      types2.EntrySource `json:"source,omitempty"`
+	Weight      int                `json:"weight,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	ValidFrom   *time.Time         `json:"validFrom,omitempty"`
+	ValidUntil  *time.Time         `json:"validUntil,omitempty"`
+}
+
+func (e cachedEntry) equal(other cachedEntry) bool {
+	return e.Category == other.Category &&
+		e.DisplayName == other.DisplayName &&
+		e.Href == other.Href &&
+		e.Title == other.Title &&
+		e.Target == other.Target &&
+		e.ID == other.ID &&
+		e.Source == other.Source &&
+		e.Weight == other.Weight &&
+		slices.Equal(e.Tags, other.Tags) &&
+		equalTimes(e.ValidFrom, other.ValidFrom) &&
+		equalTimes(e.ValidUntil, other.ValidUntil)
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// optionalTime returns nil for the zero time, so unbounded entries are persisted without bounds.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func compareCachedEntries(a, b cachedEntry) int {
+	return cmp.Or(
+		cmp.Compare(a.Category, b.Category),
+		cmp.Compare(a.Href, b.Href),
+		cmp.Compare(a.ID, b.ID),
+		cmp.Compare(a.DisplayName, b.DisplayName),
+	)
+}
+
+// SourceCache keeps the last successful result of every source, so a source that fails temporarily does not
+// disappear from the warp menu.
+type SourceCache struct {
+	mutex   sync.Mutex
+	sources map[string]cachedSource
+	// changed is true if the entries of a source changed since the cache was persisted.
+	changed bool
+}
+
+// NewSourceCache creates an empty source cache.
+func NewSourceCache() *SourceCache {
+	return &SourceCache{sources: map[string]cachedSource{}}
+}
+
+// Store replaces the cached result of the source. The entries are kept with their category, tags and validity, so
+// transform rules and the validity filter handle cached entries like read ones. The cache only counts as changed if the entries differ, because the read
+// time alone is not worth persisting.
+func (c *SourceCache) Store(source string, entries []types2.EntryWithCategory, readAt time.Time) {
+	cachedEntries := make([]cachedEntry, 0, len(entries))
+	for _, entry := range entries {
+		cachedEntries = append(cachedEntries, cachedEntry{
+			Category:    entry.Category,
+			DisplayName: entry.Entry.DisplayName,
+			Href:        entry.Entry.Href,
+			Title:       entry.Entry.Title,
+			Target:      entry.Entry.Target,
+			ID:          entry.Entry.ID,
+			Source:      entry.Entry.Source,
+			Weight:      entry.Entry.Weight,
+			Tags:        slices.Clone(entry.Tags),
+			ValidFrom:   optionalTime(entry.ValidFrom),
+			ValidUntil:  optionalTime(entry.ValidUntil),
+		})
+	}
+	// sources may return their entries in any order
+	slices.SortStableFunc(cachedEntries, compareCachedEntries)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if existing, ok := c.sources[source]; !ok || !slices.EqualFunc(existing.Entries, cachedEntries, cachedEntry.equal) {
+		c.changed = true
+	}
+	c.sources[source] = cachedSource{ReadAt: readAt.UTC(), Entries: cachedEntries}
+}
+
+// Changed returns true if the entries of a source changed since the cache was marked as persisted.
+func (c *SourceCache) Changed() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.changed
+}
+
+// MarkPersisted marks the current entries as persisted.
+func (c *SourceCache) MarkPersisted() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.changed = false
+}
+
+// Load returns the cached entries of the source and the time they were read.
+func (c *SourceCache) Load(source string) ([]types2.EntryWithCategory, time.Time, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, ok := c.sources[source]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	var entries []types2.EntryWithCategory
+	for _, entry := range cached.Entries {
+		entries = append(entries, types2.EntryWithCategory{
+			Entry: types2.Entry{
+				DisplayName: entry.DisplayName,
+				Href:        entry.Href,
+				Title:       entry.Title,
+				Target:      entry.Target,
+				ID:          entry.ID,
+				Source:      entry.Source,
+				Weight:      entry.Weight,
+			},
+			Category:   entry.Category,
+			Tags:       slices.Clone(entry.Tags),
+			ValidFrom:  timeOrZero(entry.ValidFrom),
+			ValidUntil: timeOrZero(entry.ValidUntil),
+		})
+	}
+	return entries, cached.ReadAt, true
+}
+
+// Marshal returns the cache as JSON to persist it.
+func (c *SourceCache) Marshal() ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, err := json.Marshal(c.sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal source cache: %w", err)
+	}
+	return data, nil
+}
+
+// Unmarshal adds persisted results to the cache. Results that are already cached are kept, because they are newer.
+func (c *SourceCache) Unmarshal(data []byte) error {
+	sources := map[string]cachedSource{}
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return fmt.Errorf("failed to unmarshal source cache: %w", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for source, cached := range sources {
+		if _, ok := c.sources[source]; !ok {
+			c.sources[source] = cached
+		}
+	}
+	return nil
+}
+
+func sortStaleSources(staleSources []StaleSource) {
+	sort.Slice(staleSources, func(i, j int) bool {
+		return staleSources[i].Source < staleSources[j].Source
+	})
+}
+
+// loadSourceCache adds the persisted results to the source cache once after the start of the controller.
+func (r *WarpMenuConfigReconciler) loadSourceCache(ctx context.Context, namespace string) error {
+	if r.sourceCacheLoaded {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types3.NamespacedName{Name: config.WarpSourceCacheConfigMap, Namespace: namespace}, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get warp menu source cache configmap: %w", err)
+	}
+
+	if data, ok := configMap.Data[sourceCacheDataKey]; ok {
+		if err = r.sourceCache.Unmarshal([]byte(data)); err != nil {
+			ctrl.Log.Info(fmt.Sprintf("ignoring invalid warp menu source cache: %s", err.Error()))
+		}
+	}
+
+	r.sourceCacheLoaded = true
+	return nil
+}
+
+// persistSourceCache stores the source cache in a configmap. The configmap is only updated if cached entries changed.
+func (r *WarpMenuConfigReconciler) persistSourceCache(ctx context.Context, namespace string) error {
+	if !r.sourceCache.Changed() {
+		return nil
+	}
+
+	data, err := r.sourceCache.Marshal()
+	if err != nil {
+		return err
+	}
+
+	err = r.writeConfigMapData(ctx, namespace, config.WarpSourceCacheConfigMap, sourceCacheDataKey, string(data), "warp menu source cache")
+	if err != nil {
+		return err
+	}
+	r.sourceCache.MarkPersisted()
+	return nil
+}
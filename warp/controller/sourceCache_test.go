@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var cachedEntries = []types2.EntryWithCategory{
+	{Category: "Development Apps/CI", Entry: types2.Entry{DisplayName: "Jenkins", Href: "/jenkins", Target: types2.TARGET_SELF, ID: "jenkins", Source: types2.SourceDogu}, Tags: []string{"warp", "ci"}},
+	{Category: "Development Apps", Entry: types2.Entry{DisplayName: "Redmine", Href: "/redmine", Target: types2.TARGET_SELF, ID: "redmine", Source: types2.SourceDogu, Weight: 5}, Tags: []string{"warp"}},
+}
+
+func TestSourceCache(t *testing.T) {
+	readAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("should load stored entries with category path and tags", func(t *testing.T) {
+		// given
+		cache := NewSourceCache()
+		cache.Store("dogus:/dogu", cachedEntries, readAt)
+
+		// when
+		entries, actualReadAt, ok := cache.Load("dogus:/dogu")
+
+		// then
+		require.True(t, ok)
+		assert.Equal(t, readAt, actualReadAt)
+		expected := []types2.EntryWithCategory{
+			{Category: "Development Apps", Entry: types2.Entry{DisplayName: "Redmine", Href: "/redmine", Target: types2.TARGET_SELF, ID: "redmine", Source: types2.SourceDogu, Weight: 5}, Tags: []string{"warp"}},
+			{Category: "Development Apps/CI", Entry: types2.Entry{DisplayName: "Jenkins", Href: "/jenkins", Target: types2.TARGET_SELF, ID: "jenkins", Source: types2.SourceDogu}, Tags: []string{"warp", "ci"}},
+		}
+		assert.Equal(t, expected, entries)
+	})
+
+	t.Run("should not load unknown source", func(t *testing.T) {
+		// when
+		_, _, ok := NewSourceCache().Load("dogus:/dogu")
+
+		// then
+		assert.False(t, ok)
+	})
+
+	t.Run("should not change stored entries if read entries change", func(t *testing.T) {
+		// given
+		cache := NewSourceCache()
+		entries := []types2.EntryWithCategory{{Category: "Links", Entry: types2.Entry{DisplayName: "Docs", Href: "/docs"}, Tags: []string{"warp"}}}
+		cache.Store("externals:externals", entries, readAt)
+
+		// when
+		entries[0].Entry.Href = "/changed"
+		entries[0].Tags[0] = "changed"
+
+		// then
+		loaded, _, _ := cache.Load("externals:externals")
+		assert.Equal(t, "/docs", loaded[0].Entry.Href)
+		assert.Equal(t, []string{"warp"}, loaded[0].Tags)
+	})
+
+	t.Run("should keep newer entries when unmarshalling", func(t *testing.T) {
+		// given
+		persisted := NewSourceCache()
+		persisted.Store("dogus:/dogu", cachedEntries, readAt)
+		persisted.Store("externals:externals", []types2.EntryWithCategory{{Category: "Links", Entry: types2.Entry{DisplayName: "Old", Href: "/old", Target: types2.TARGET_EXTERNAL}}}, readAt)
+		data, err := persisted.Marshal()
+		require.NoError(t, err)
+		cache := NewSourceCache()
+		cache.Store("externals:externals", []types2.EntryWithCategory{{Category: "Links", Entry: types2.Entry{DisplayName: "New", Href: "/new", Target: types2.TARGET_EXTERNAL}}}, readAt.Add(time.Hour))
+
+		// when
+		err = cache.Unmarshal(data)
+
+		// then
+		require.NoError(t, err)
+		dogus, _, ok := cache.Load("dogus:/dogu")
+		require.True(t, ok)
+		require.Len(t, dogus, 2)
+		assert.Equal(t, []string{"warp"}, dogus[0].Tags)
+		externals, _, _ := cache.Load("externals:externals")
+		assert.Equal(t, "New", externals[0].Entry.DisplayName)
+	})
+
+	t.Run("should only count changed entries as change", func(t *testing.T) {
+		// given
+		cache := NewSourceCache()
+		cache.Store("dogus:/dogu", cachedEntries, readAt)
+		require.True(t, cache.Changed())
+		cache.MarkPersisted()
+
+		// when
+		cache.Store("dogus:/dogu", []types2.EntryWithCategory{cachedEntries[1], cachedEntries[0]}, readAt.Add(time.Hour))
+
+		// then
+		assert.False(t, cache.Changed())
+		changedTags := slices.Clone(cachedEntries)
+		changedTags[0].Tags = []string{"warp"}
+		cache.Store("dogus:/dogu", changedTags, readAt.Add(2*time.Hour))
+		assert.True(t, cache.Changed())
+	})
+
+	t.Run("should keep validity of persisted entries", func(t *testing.T) {
+		// given
+		validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		validUntil := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		persisted := NewSourceCache()
+		persisted.Store("externals:externals", []types2.EntryWithCategory{
+			{Category: "Links", Entry: types2.Entry{DisplayName: "Maintenance", Href: "/maintenance", Target: types2.TARGET_SELF}, ValidFrom: validFrom, ValidUntil: validUntil},
+			{Category: "Links", Entry: types2.Entry{DisplayName: "Docs", Href: "/docs", Target: types2.TARGET_SELF}},
+		}, readAt)
+		data, err := persisted.Marshal()
+		require.NoError(t, err)
+		cache := NewSourceCache()
+
+		// when
+		err = cache.Unmarshal(data)
+
+		// then
+		require.NoError(t, err)
+		entries, _, ok := cache.Load("externals:externals")
+		require.True(t, ok)
+		require.Len(t, entries, 2)
+		assert.True(t, entries[0].ValidFrom.IsZero())
+		assert.True(t, entries[0].ValidUntil.IsZero())
+		assert.Equal(t, validFrom, entries[1].ValidFrom)
+		assert.Equal(t, validUntil, entries[1].ValidUntil)
+	})
+
+	t.Run("should count changed validity as change", func(t *testing.T) {
+		// given
+		cache := NewSourceCache()
+		entries := []types2.EntryWithCategory{{Category: "Links", Entry: types2.Entry{DisplayName: "Docs", Href: "/docs"}}}
+		cache.Store("externals:externals", entries, readAt)
+		cache.MarkPersisted()
+
+		// when
+		entries[0].ValidUntil = readAt.Add(time.Hour)
+		cache.Store("externals:externals", entries, readAt)
+
+		// then
+		assert.True(t, cache.Changed())
+	})
+
+	t.Run("should fail to unmarshal invalid data", func(t *testing.T) {
+		// when
+		err := NewSourceCache().Unmarshal([]byte("{invalid"))
+
+		// then
+		assert.ErrorContains(t, err, "failed to unmarshal source cache")
+	})
+}
+
+func TestWarpMenuConfigReconciler_loadSourceCache(t *testing.T) {
+	cacheKey := types.NamespacedName{Name: config.WarpSourceCacheConfigMap, Namespace: testNamespace}
+
+	t.Run("should load persisted cache only once", func(t *testing.T) {
+		// given
+		persisted := NewSourceCache()
+		persisted.Store("dogus:/dogu", cachedEntries, time.Now())
+		data, err := persisted.Marshal()
+		require.NoError(t, err)
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, cacheKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{sourceCacheDataKey: string(data)}
+			}).
+			Return(nil).Once()
+		reconciler := &WarpMenuConfigReconciler{client: clientMock, sourceCache: NewSourceCache()}
+
+		// when
+		err = reconciler.loadSourceCache(testCtx, testNamespace)
+		require.NoError(t, err)
+		err = reconciler.loadSourceCache(testCtx, testNamespace)
+
+		// then
+		require.NoError(t, err)
+		_, _, ok := reconciler.sourceCache.Load("dogus:/dogu")
+		assert.True(t, ok)
+	})
+
+	t.Run("should accept missing configmap", func(t *testing.T) {
+		// given
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, cacheKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpSourceCacheConfigMap))
+		reconciler := &WarpMenuConfigReconciler{client: clientMock, sourceCache: NewSourceCache()}
+
+		// when
+		err := reconciler.loadSourceCache(testCtx, testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, reconciler.sourceCacheLoaded)
+	})
+
+	t.Run("should fail to get configmap", func(t *testing.T) {
+		// given
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, cacheKey, mock.AnythingOfType("*v1.ConfigMap")).Return(assert.AnError)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock, sourceCache: NewSourceCache()}
+
+		// when
+		err := reconciler.loadSourceCache(testCtx, testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.False(t, reconciler.sourceCacheLoaded)
+	})
+}
+
+func TestWarpMenuConfigReconciler_persistSourceCache(t *testing.T) {
+	t.Run("should create source cache configmap", func(t *testing.T) {
+		// given
+		cache := NewSourceCache()
+		cache.Store("dogus:/dogu", cachedEntries, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, types.NamespacedName{Name: config.WarpSourceCacheConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpSourceCacheConfigMap))
+		clientMock.EXPECT().Create(testCtx, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
+				configMap := obj.(*v1.ConfigMap)
+				assert.Equal(t, config.WarpSourceCacheConfigMap, configMap.Name)
+				assert.Contains(t, configMap.Data[sourceCacheDataKey], `"category":"Development Apps/CI"`)
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock, sourceCache: cache}
+
+		// when
+		err := reconciler.persistSourceCache(testCtx, testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, cache.Changed())
+	})
+
+	t.Run("should not update configmap if cached entries did not change", func(t *testing.T) {
+		// given
+		readAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		cache := NewSourceCache()
+		cache.Store("dogus:/dogu", cachedEntries, readAt)
+		cache.MarkPersisted()
+		cache.Store("dogus:/dogu", cachedEntries, readAt.Add(time.Minute))
+		reconciler := &WarpMenuConfigReconciler{client: newMockK8sClient(t), sourceCache: cache}
+
+		// when
+		err := reconciler.persistSourceCache(testCtx, testNamespace)
+
+		// then
+		require.NoError(t, err)
+	})
+}
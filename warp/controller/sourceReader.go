@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	libconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	sourceFieldPath = "Path"
+	sourceFieldTag  = "Tag"
+)
+
+// SourceReader reads the warp menu entries of one source type. Readers are registered in a SourceReaderRegistry.
+type SourceReader interface {
+	// Type returns the source type in the warp config, e.g. "dogus".
+	Type() string
+	// Schema returns the fields of the source configuration used by the reader.
+	Schema() SourceSchema
+	// WatchTriggers returns the changes after which the source has to be read again.
+	WatchTriggers(source config.Source) SourceWatchTriggers
+	// Read returns the entries of the source. The global config is the snapshot shared by all sources. If it could not
+	// be read, globalConfigErr is set and the snapshot is empty, so readers using it have to fail instead of returning
+	// no entries.
+	Read(ctx context.Context, source config.Source, globalConfig libconfig.GlobalConfig, globalConfigErr error) (SourceResult, error)
+}
+
+// TemplateSourceReader is implemented by source readers whose entries are maintained in the configuration of the
+// installation, so their href and description can contain template variables like "{{ .fqdn }}". Entries of other
+// sources, e.g. dogus, are never rendered.
+type TemplateSourceReader interface {
+	SourceReader
+	// RendersTemplates returns whether the href and the description of the entries are rendered.
+	RendersTemplates() bool
+}
+
+// SourceResult contains the entries read from a source.
+type SourceResult struct {
+	Entries []types2.EntryWithCategory
+	// RejectedEntries contains the entries that were skipped because they are invalid.
+	RejectedEntries []RejectedEntry
+}
+
+// SourceSchema describes the fields of config.Source a source type uses, e.g. "Path". Type and Timeout are used by
+// all source types.
+type SourceSchema struct {
+	RequiredFields []string
+	OptionalFields []string
+}
+
+// SourceWatchTriggers contains the changes after which a source has to be read again.
+type SourceWatchTriggers struct {
+	// GlobalConfigKeys contains the global config keys and directories the source reads.
+	GlobalConfigKeys []string
+	// DoguVersions is set if the source depends on the installed dogus.
+	DoguVersions bool
+}
+
+// SourceReaderRegistry contains the source readers by their type.
+type SourceReaderRegistry struct {
+	mutex   sync.RWMutex
+	readers map[string]SourceReader
+}
+
+// NewSourceReaderRegistry creates a registry with the given readers. It panics if two readers have the same type.
+func NewSourceReaderRegistry(readers ...SourceReader) *SourceReaderRegistry {
+	registry := &SourceReaderRegistry{readers: map[string]SourceReader{}}
+	for _, reader := range readers {
+		if err := registry.Register(reader); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// Register adds a reader for a new source type.
+func (r *SourceReaderRegistry) Register(reader SourceReader) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if reader.Type() == "" || reader.Type() == supportEntryConfigSourceType {
+		return fmt.Errorf("invalid source type %q", reader.Type())
+	}
+	if _, exists := r.readers[reader.Type()]; exists {
+		return fmt.Errorf("source reader for type %q is already registered", reader.Type())
+	}
+
+	r.readers[reader.Type()] = reader
+	return nil
+}
+
+// Get returns the reader of the source type.
+func (r *SourceReaderRegistry) Get(sourceType string) (SourceReader, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	reader, ok := r.readers[sourceType]
+	return reader, ok
+}
+
+// Types returns the registered source types, sorted by name.
+func (r *SourceReaderRegistry) Types() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	types := make([]string, 0, len(r.readers))
+	for sourceType := range r.readers {
+		types = append(types, sourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// WatchTriggers returns the combined watch triggers of all configured sources.
+func (r *SourceReaderRegistry) WatchTriggers(sources []config.Source) SourceWatchTriggers {
+	var triggers SourceWatchTriggers
+	for _, source := range sources {
+		reader, ok := r.Get(source.Type)
+		if !ok {
+			continue
+		}
+
+		sourceTriggers := reader.WatchTriggers(source)
+		triggers.GlobalConfigKeys = append(triggers.GlobalConfigKeys, sourceTriggers.GlobalConfigKeys...)
+		triggers.DoguVersions = triggers.DoguVersions || sourceTriggers.DoguVersions
+	}
+	return triggers
+}
+
+// validateSource returns an error if a required field of the schema is missing. Fields the schema does not use are
+// logged, because they have no effect.
+func validateSource(source config.Source, schema SourceSchema) error {
+	fields := map[string]string{sourceFieldPath: source.Path, sourceFieldTag: source.Tag}
+	for _, field := range schema.RequiredFields {
+		if fields[field] == "" {
+			return fmt.Errorf("field %s is required for sources of type %s", field, source.Type)
+		}
+	}
+
+	for field, value := range fields {
+		if value != "" && !StringInSlice(field, schema.RequiredFields) && !StringInSlice(field, schema.OptionalFields) {
+			ctrl.Log.Info(fmt.Sprintf("Field %s is not used for sources of type %s", field, source.Type))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	registryconfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/warp-assets/config"
+	types2 "github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSourceReader struct {
+	sourceType string
+	schema     SourceSchema
+	triggers   SourceWatchTriggers
+	entries    []types2.EntryWithCategory
+	templates  bool
+	// globalConfigErr is the error of the global config passed to the last Read.
+	globalConfigErr error
+}
+
+func (r *testSourceReader) Type() string {
+	return r.sourceType
+}
+
+func (r *testSourceReader) Schema() SourceSchema {
+	return r.schema
+}
+
+func (r *testSourceReader) RendersTemplates() bool {
+	return r.templates
+}
+
+func (r *testSourceReader) WatchTriggers(config.Source) SourceWatchTriggers {
+	return r.triggers
+}
+
+func (r *testSourceReader) Read(_ context.Context, _ config.Source, _ registryconfig.GlobalConfig, globalConfigErr error) (SourceResult, error) {
+	r.globalConfigErr = globalConfigErr
+	if globalConfigErr != nil {
+		return SourceResult{}, globalConfigErr
+	}
+	return SourceResult{Entries: r.entries}, nil
+}
+
+func TestSourceReaderRegistry_Register(t *testing.T) {
+	t.Run("should register reader", func(t *testing.T) {
+		// given
+		registry := NewSourceReaderRegistry()
+		reader := &testSourceReader{sourceType: "static"}
+
+		// when
+		err := registry.Register(reader)
+
+		// then
+		require.NoError(t, err)
+		registered, ok := registry.Get("static")
+		assert.True(t, ok)
+		assert.Same(t, reader, registered)
+	})
+
+	t.Run("should fail for duplicate type", func(t *testing.T) {
+		// given
+		registry := NewSourceReaderRegistry(&testSourceReader{sourceType: "static"})
+
+		// when
+		err := registry.Register(&testSourceReader{sourceType: "static"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "source reader for type \"static\" is already registered")
+	})
+
+	t.Run("should fail for empty type", func(t *testing.T) {
+		// when
+		err := NewSourceReaderRegistry().Register(&testSourceReader{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid source type \"\"")
+	})
+
+	t.Run("should fail for support entry config type", func(t *testing.T) {
+		// when
+		err := NewSourceReaderRegistry().Register(&testSourceReader{sourceType: "support_entry_config"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid source type \"support_entry_config\"")
+	})
+
+	t.Run("should panic for duplicate type in constructor", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSourceReaderRegistry(&testSourceReader{sourceType: "static"}, &testSourceReader{sourceType: "static"})
+		})
+	})
+}
+
+func TestSourceReaderRegistry_Types(t *testing.T) {
+	// given
+	registry := NewSourceReaderRegistry(&testSourceReader{sourceType: "static"}, &DogusSourceReader{}, &ExternalsSourceReader{})
+
+	// when
+	types := registry.Types()
+
+	// then
+	assert.Equal(t, []string{"dogus", "externals", "static"}, types)
+}
+
+func TestSourceReaderRegistry_WatchTriggers(t *testing.T) {
+	// given
+	registry := NewSourceReaderRegistry(&testSourceReader{sourceType: "static", triggers: SourceWatchTriggers{GlobalConfigKeys: []string{"static"}}}, &DogusSourceReader{}, &ExternalsSourceReader{})
+	sources := []config.Source{{Type: "static"}, {Type: "externals", Path: "/config/_global/externals"}, {Type: "unknown"}}
+
+	// when
+	triggers := registry.WatchTriggers(sources)
+
+	// then
+	assert.Equal(t, SourceWatchTriggers{GlobalConfigKeys: []string{"static", "externals"}}, triggers)
+}
+
+func Test_validateSource(t *testing.T) {
+	t.Run("should accept source with required fields", func(t *testing.T) {
+		// when
+		err := validateSource(config.Source{Type: "externals", Path: "externals"}, NewExternalsSourceReader().Schema())
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept unused fields", func(t *testing.T) {
+		// when
+		err := validateSource(config.Source{Type: "externals", Path: "externals", Tag: "warp"}, NewExternalsSourceReader().Schema())
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail for missing required field", func(t *testing.T) {
+		// when
+		err := validateSource(config.Source{Type: "externals"}, NewExternalsSourceReader().Schema())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "field Path is required for sources of type externals")
+	})
+}
+
+func TestConfigReader_Read_customSourceReader(t *testing.T) {
+	t.Run("should read source of registered type", func(t *testing.T) {
+		// given
+		entry := getEntryWithCategory("Status", "https://status.example.com", "Status", "Links", types2.TARGET_EXTERNAL)
+		configuration := &config.Configuration{Sources: []config.Source{{Type: "static"}}}
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, nil)
+		registry := NewSourceReaderRegistry(&testSourceReader{sourceType: "static", entries: []types2.EntryWithCategory{entry}})
+		reader := NewConfigReader(configuration, globalConfigRepoMock, registry, nil)
+
+		// when
+		categories, err := reader.Read(testCtx, configuration)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, categories, 1)
+		assert.Equal(t, "Links", categories[0].Title)
+		assert.Equal(t, "Status", categories[0].Entries[0].DisplayName)
+	})
+
+	t.Run("should pass error of global config to reader without global config keys", func(t *testing.T) {
+		// given
+		entry := getEntryWithCategory("Status", "https://status.example.com", "Status", "Links", types2.TARGET_EXTERNAL)
+		configuration := &config.Configuration{Sources: []config.Source{{Type: "static"}}}
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, assert.AnError)
+		sourceReader := &testSourceReader{sourceType: "static", entries: []types2.EntryWithCategory{entry}}
+		sourceCache := NewSourceCache()
+		sourceCache.Store("static:", []types2.EntryWithCategory{entry}, time.Now())
+		reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(sourceReader), sourceCache)
+
+		// when
+		categories, err := reader.Read(testCtx, configuration)
+
+		// then
+		require.NoError(t, err)
+		assert.ErrorIs(t, sourceReader.globalConfigErr, assert.AnError)
+		require.Len(t, categories, 1)
+		assert.Equal(t, "Status", categories[0].Entries[0].DisplayName)
+		require.Len(t, reader.StaleSources(), 1)
+		assert.Equal(t, "static:", reader.StaleSources()[0].Source)
+	})
+
+	t.Run("should skip source of unknown type", func(t *testing.T) {
+		// given
+		configuration := &config.Configuration{Sources: []config.Source{{Type: "static"}}}
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(testCtx).Return(registryconfig.GlobalConfig{}, nil)
+		reader := NewConfigReader(configuration, globalConfigRepoMock, NewSourceReaderRegistry(), nil)
+
+		// when
+		categories, err := reader.Read(testCtx, configuration)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, categories)
+	})
+}
@@ -1,10 +1,23 @@
 package types
 
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CategoryPathSeparator separates the titles of nested categories, e.g. "Development Apps/CI".
+const CategoryPathSeparator = "/"
+
 // Category categories multiple entries in the warp menu
 type Category struct {
-	Title   string
-	Order   int
-	Entries Entries
+	Title       string
+	Order       int
+	Icon        string `json:",omitempty"`
+	Description string `json:",omitempty"`
+	Collapsed   bool   `json:",omitempty"`
+	Entries     Entries
+	Children    Categories `json:",omitempty"`
 }
 
 func (c Category) String() string {
@@ -31,18 +44,148 @@ func (c Categories) Swap(i, j int) {
 
 // InsertCategories adds new categories to the slice.
 func (c *Categories) InsertCategories(newCategories Categories) {
+	c.InsertCategoriesWithPolicy(newCategories, DefaultMergePolicy)
+}
+
+// InsertCategoriesWithPolicy adds new categories to the slice and resolves duplicate entries with the given policy.
+func (c *Categories) InsertCategoriesWithPolicy(newCategories Categories, policy MergePolicy) {
 	for _, newCategory := range newCategories {
-		c.InsertCategory(newCategory)
+		c.InsertCategoryWithPolicy(newCategory, policy)
 	}
 }
 
 // InsertCategory adds a new category to the slice. If the title are same the entries will be merged.
+// Duplicate entries are resolved with the DefaultMergePolicy.
 func (c *Categories) InsertCategory(newCategory *Category) {
+	c.InsertCategoryWithPolicy(newCategory, DefaultMergePolicy)
+}
+
+// InsertNestedCategory works like InsertCategory, but a title containing the CategoryPathSeparator is inserted as
+// child of the categories named by the path. Missing parent categories are created.
+func (c *Categories) InsertNestedCategory(newCategory *Category) {
+	c.InsertNestedCategoryWithPolicy(newCategory, DefaultMergePolicy)
+}
+
+// InsertNestedCategoryWithPolicy works like InsertNestedCategory but resolves duplicate entries with the given policy.
+func (c *Categories) InsertNestedCategoryWithPolicy(newCategory *Category, policy MergePolicy) {
+	head, rest := splitCategoryPath(newCategory.Title)
+	if rest == "" {
+		category := *newCategory
+		category.Title = head
+		c.InsertCategoryWithPolicy(&category, policy)
+		return
+	}
+
+	parent := &Category{Title: head, Entries: Entries{}}
+	parent.Children.InsertNestedCategoryWithPolicy(&Category{
+		Title:       rest,
+		Order:       newCategory.Order,
+		Icon:        newCategory.Icon,
+		Description: newCategory.Description,
+		Collapsed:   newCategory.Collapsed,
+		Entries:     newCategory.Entries,
+		Children:    newCategory.Children,
+	}, policy)
+	c.InsertCategoryWithPolicy(parent, policy)
+}
+
+// InsertCategoryWithPolicy works like InsertCategory but resolves duplicate entries with the given policy.
+// The result does not depend on the order in which categories are inserted.
+func (c *Categories) InsertCategoryWithPolicy(newCategory *Category, policy MergePolicy) {
+	c.mergeCategory(newCategory)
+	*c = c.deduplicate(policy)
+}
+
+// mergeCategory merges the category into the category with the same title or inserts a copy of it, so later merges
+// never change the categories of the caller.
+func (c *Categories) mergeCategory(newCategory *Category) {
 	for _, category := range *c {
 		if category.Title == newCategory.Title {
+			category.Order = max(category.Order, newCategory.Order)
 			category.Entries = append(category.Entries, newCategory.Entries...)
+			category.sortEntries()
+			for _, child := range newCategory.Children {
+				category.Children.mergeCategory(child)
+			}
 			return
 		}
 	}
-	*c = append(*c, newCategory)
+	category := *newCategory
+	category.Entries = slices.Clone(newCategory.Entries)
+	category.Children = nil
+	for _, child := range newCategory.Children {
+		category.Children.mergeCategory(child)
+	}
+	*c = append(*c, &category)
+}
+
+// SortRecursive sorts the categories, their entries and all child categories.
+func (c Categories) SortRecursive() {
+	for _, category := range c {
+		category.sortEntries()
+		category.Children.SortRecursive()
+	}
+	sort.Sort(c)
+}
+
+// Flatten returns the categories without nesting for consumers that do not support child categories.
+// Child categories are placed directly after their parent and their title contains the whole category path.
+// Categories without own entries are omitted.
+func (c Categories) Flatten() Categories {
+	return c.flatten("")
+}
+
+func (c Categories) flatten(parentPath string) Categories {
+	result := Categories{}
+	for _, category := range c {
+		path := joinCategoryPath(parentPath, category.Title)
+		if len(category.Entries) > 0 {
+			result = append(result, &Category{
+				Title:       path,
+				Order:       category.Order,
+				Icon:        category.Icon,
+				Description: category.Description,
+				Collapsed:   category.Collapsed,
+				Entries:     category.Entries,
+			})
+		}
+		result = append(result, category.Children.flatten(path)...)
+	}
+	return result
+}
+
+// EntriesWithCategory returns all entries together with the path of their category, e.g. "Development Apps/CI".
+func (c Categories) EntriesWithCategory() []EntryWithCategory {
+	return c.entriesWithCategory("")
+}
+
+func (c Categories) entriesWithCategory(parentPath string) []EntryWithCategory {
+	var result []EntryWithCategory
+	for _, category := range c {
+		path := joinCategoryPath(parentPath, category.Title)
+		for _, entry := range category.Entries {
+			result = append(result, EntryWithCategory{Entry: entry, Category: path})
+		}
+		result = append(result, category.Children.entriesWithCategory(path)...)
+	}
+	return result
+}
+
+// splitCategoryPath returns the first element of a category path and the remaining path.
+func splitCategoryPath(path string) (string, string) {
+	var parts []string
+	for _, part := range strings.Split(path, CategoryPathSeparator) {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return path, ""
+	case 1:
+		return parts[0], ""
+	default:
+		return parts[0], strings.Join(parts[1:], CategoryPathSeparator)
+	}
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -127,3 +128,138 @@ func TestCategory_String(t *testing.T) {
 	// then
 	assert.Equal(t, "title", str)
 }
+
+func TestCategories_insertNestedCategory(t *testing.T) {
+	t.Run("should create missing parent category", func(t *testing.T) {
+		// given
+		categories := Categories{}
+		ciEntry := Entry{DisplayName: "Jenkins"}
+		add := &Category{Order: 10, Title: "Development Apps/CI", Entries: Entries{ciEntry}}
+
+		// when
+		categories.InsertNestedCategory(add)
+
+		// then
+		require.Equal(t, 1, len(categories))
+		assert.Equal(t, "Development Apps", categories[0].Title)
+		assert.Empty(t, categories[0].Entries)
+		require.Equal(t, 1, len(categories[0].Children))
+		assert.Equal(t, "CI", categories[0].Children[0].Title)
+		assert.Equal(t, 10, categories[0].Children[0].Order)
+		assert.Equal(t, Entries{ciEntry}, categories[0].Children[0].Entries)
+	})
+
+	t.Run("should merge into existing child category", func(t *testing.T) {
+		// given
+		jenkins := Entry{DisplayName: "Jenkins"}
+		redmine := Entry{DisplayName: "Redmine"}
+		sonar := Entry{DisplayName: "SonarQube"}
+		ci := &Category{Title: "CI", Entries: Entries{jenkins}}
+		categories := Categories{{Title: "Development Apps", Entries: Entries{redmine}, Children: Categories{ci}}}
+
+		// when
+		categories.InsertNestedCategory(&Category{Title: " Development Apps / CI ", Entries: Entries{sonar}})
+
+		// then
+		require.Equal(t, 1, len(categories))
+		assert.Equal(t, Entries{redmine}, categories[0].Entries)
+		require.Equal(t, 1, len(categories[0].Children))
+		assert.Equal(t, Entries{jenkins, sonar}, categories[0].Children[0].Entries)
+	})
+
+	t.Run("should merge children of categories with same title", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Development Apps", Children: Categories{{Title: "CI"}}}}
+		add := &Category{Title: "Development Apps", Children: Categories{{Title: "Wiki"}}}
+
+		// when
+		categories.InsertCategory(add)
+
+		// then
+		require.Equal(t, 1, len(categories))
+		require.Equal(t, 2, len(categories[0].Children))
+		assert.Equal(t, "CI", categories[0].Children[0].Title)
+		assert.Equal(t, "Wiki", categories[0].Children[1].Title)
+	})
+
+	t.Run("should not nest title containing a slash", func(t *testing.T) {
+		// given
+		categories := Categories{}
+
+		// when
+		categories.InsertCategory(&Category{Order: 50, Title: "CI/CD", Entries: Entries{{DisplayName: "Jenkins"}}})
+
+		// then
+		require.Equal(t, 1, len(categories))
+		assert.Equal(t, "CI/CD", categories[0].Title)
+		assert.Equal(t, 50, categories[0].Order)
+		assert.Empty(t, categories[0].Children)
+	})
+
+	t.Run("should not change inserted category", func(t *testing.T) {
+		// given
+		jenkins := Entry{DisplayName: "Jenkins"}
+		sonar := Entry{DisplayName: "SonarQube"}
+		add := &Category{Title: "CI", Entries: make(Entries, 1, 2), Children: Categories{{Title: "Quality"}}}
+		add.Entries[0] = jenkins
+		categories := Categories{}
+
+		// when
+		categories.InsertCategory(add)
+		categories.InsertCategory(&Category{Title: "CI", Entries: Entries{sonar}, Children: Categories{{Title: "Security"}}})
+
+		// then
+		assert.Equal(t, Entries{jenkins}, add.Entries)
+		assert.Equal(t, jenkins, add.Entries[:2][0])
+		assert.Equal(t, Entry{}, add.Entries[:2][1])
+		assert.Len(t, add.Children, 1)
+		assert.Len(t, categories[0].Children, 2)
+	})
+}
+
+func TestCategories_SortRecursive(t *testing.T) {
+	// given
+	categories := Categories{
+		{Title: "B", Children: Categories{
+			{Title: "Y", Entries: Entries{{DisplayName: "b"}, {DisplayName: "a"}}},
+			{Title: "X"},
+		}},
+		{Title: "A"},
+	}
+
+	// when
+	categories.SortRecursive()
+
+	// then
+	assert.Equal(t, "A", categories[0].Title)
+	assert.Equal(t, "B", categories[1].Title)
+	assert.Equal(t, "X", categories[1].Children[0].Title)
+	assert.Equal(t, "Y", categories[1].Children[1].Title)
+	assert.Equal(t, "a", categories[1].Children[1].Entries[0].DisplayName)
+}
+
+func TestCategories_Flatten(t *testing.T) {
+	// given
+	redmine := Entry{DisplayName: "Redmine"}
+	jenkins := Entry{DisplayName: "Jenkins"}
+	docs := Entry{DisplayName: "Docs"}
+	categories := Categories{
+		{Title: "Development Apps", Order: 100, Entries: Entries{redmine}, Children: Categories{
+			{Title: "CI", Order: 10, Entries: Entries{jenkins}},
+		}},
+		{Title: "Documentation", Children: Categories{
+			{Title: "Manuals", Entries: Entries{docs}},
+		}},
+	}
+
+	// when
+	flat := categories.Flatten()
+
+	// then
+	expected := Categories{
+		{Title: "Development Apps", Order: 100, Entries: Entries{redmine}},
+		{Title: "Development Apps/CI", Order: 10, Entries: Entries{jenkins}},
+		{Title: "Documentation/Manuals", Entries: Entries{docs}},
+	}
+	assert.Equal(t, expected, flat)
+}
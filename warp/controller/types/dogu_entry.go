@@ -60,15 +60,22 @@ func mapDoguEntry(entry doguEntry) (EntryWithCategory, error) {
 			Title:       entry.Description,
 			Target:      TARGET_SELF,
 			Href:        createDoguHref(entry.Name),
+			ID:          simpleDoguName(entry.Name),
+			Source:      SourceDogu,
 		},
 		Category: entry.Category,
+		Tags:     entry.Tags,
 	}, nil
 }
 
 func createDoguHref(name string) string {
+	return "/" + simpleDoguName(name)
+}
+
+func simpleDoguName(name string) string {
 	// remove namespace
 	parts := strings.Split(name, "/")
-	return "/" + parts[len(parts)-1]
+	return parts[len(parts)-1]
 }
 
 // ContainsString returns true if the slice contains the item
@@ -118,8 +118,11 @@ func TestDoguConverter_CreateEntryWithCategoryFromDogu(t *testing.T) {
 				Href:        "/redmine",
 				Title:       "Redmine is a flexible project management web application",
 				Target:      1,
+				ID:          "redmine",
+				Source:      SourceDogu,
 			},
 				Category: "Development Apps",
+				Tags:     []string{"warp", "pm", "projectmanagement", "issue", "task"},
 			},
 			wantErr: assert.NoError,
 		},
@@ -131,8 +134,11 @@ func TestDoguConverter_CreateEntryWithCategoryFromDogu(t *testing.T) {
 				Href:        "/redmine",
 				Title:       "Redmine is a flexible project management web application",
 				Target:      1,
+				ID:          "redmine",
+				Source:      SourceDogu,
 			},
 				Category: "Development Apps",
+				Tags:     []string{"warp", "pm", "projectmanagement", "issue", "task"},
 			},
 			wantErr: assert.NoError,
 		},
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// maxHeadingLevel is the deepest heading used for nested categories in the HTML fragment.
+const maxHeadingLevel = 6
+
+const htmlFragmentTemplate = `{{define "categories"}}<ul>
+{{range .Categories}}<li>
+<h{{.Level}}>{{.Title}}</h{{.Level}}>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}{{if .Entries}}<ul>
+{{range .Entries}}<li><a href="{{.Href}}"{{if .Title}} title="{{.Title}}"{{end}}{{if .NewWindow}} target="_blank" rel="noopener noreferrer"{{end}}>{{.Name}}</a></li>
+{{end}}</ul>
+{{end}}{{if .Categories}}{{template "categories" .}}{{end}}</li>
+{{end}}</ul>
+{{end}}<nav class="warp-menu" aria-label="Warp menu">
+{{template "categories" .}}</nav>
+`
+
+const bookmarksTemplate = `{{define "categories"}}{{range .Categories}}<DT><H3>{{.Title}}</H3>
+<DL><p>
+{{range .Entries}}<DT><A HREF="{{.Href}}">{{.Name}}</A>
+{{end}}{{if .Categories}}{{template "categories" .}}{{end}}</DL><p>
+{{end}}{{end}}<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+{{template "categories" .}}</DL><p>
+`
+
+var (
+	htmlFragment = template.Must(template.New("htmlFragment").Parse(htmlFragmentTemplate))
+	bookmarks    = template.Must(template.New("bookmarks").Parse(bookmarksTemplate))
+)
+
+type exportCategory struct {
+	Title       string
+	Description string
+	Level       int
+	Entries     []exportEntry
+	// Categories contains the children, so they are rendered with the same template as the top-level categories.
+	Categories []exportCategory
+}
+
+type exportEntry struct {
+	Name      string
+	Title     string
+	Href      string
+	NewWindow bool
+}
+
+// RenderHTMLFragment renders the categories as a static HTML fragment that can be used if the warp menu script
+// is not available.
+func RenderHTMLFragment(categories Categories) ([]byte, error) {
+	return render(htmlFragment, categories, "")
+}
+
+// RenderBookmarks renders the categories as a Netscape bookmark file that can be imported into browsers. Relative
+// links are resolved against the base url, e.g. "https://ces.example.com".
+func RenderBookmarks(categories Categories, baseURL string) ([]byte, error) {
+	return render(bookmarks, categories, baseURL)
+}
+
+func render(tmpl *template.Template, categories Categories, baseURL string) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := tmpl.Execute(&buffer, exportCategory{Categories: toExportCategories(categories, 2, baseURL)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func toExportCategories(categories Categories, level int, baseURL string) []exportCategory {
+	var result []exportCategory
+	for _, category := range categories {
+		var entries []exportEntry
+		for _, entry := range category.Entries {
+			entries = append(entries, toExportEntry(entry, baseURL))
+		}
+
+		result = append(result, exportCategory{
+			Title:       category.Title,
+			Description: category.Description,
+			Level:       level,
+			Entries:     entries,
+			Categories:  toExportCategories(category.Children, min(level+1, maxHeadingLevel), baseURL),
+		})
+	}
+	return result
+}
+
+func toExportEntry(entry Entry, baseURL string) exportEntry {
+	name := entry.DisplayName
+	if name == "" {
+		name = entry.Title
+	}
+
+	href := entry.Href
+	if baseURL != "" && strings.HasPrefix(href, "/") && !strings.HasPrefix(href, "//") {
+		href = strings.TrimSuffix(baseURL, "/") + href
+	}
+
+	return exportEntry{
+		Name:      name,
+		Title:     entry.Title,
+		Href:      href,
+		NewWindow: entry.Target == TARGET_EXTERNAL || entry.Target == TARGET_NEW_WINDOW,
+	}
+}
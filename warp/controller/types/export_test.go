@@ -0,0 +1,105 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportCategories = Categories{
+	{Title: "Development & Co", Description: "Tools", Entries: Entries{
+		{DisplayName: "Redmine", Href: "/redmine", Title: "Project <management>", Target: TARGET_SELF},
+	}, Children: Categories{
+		{Title: "CI", Entries: Entries{{DisplayName: "Builds", Href: "https://ci.example.com/?a=1&b=2", Target: TARGET_EXTERNAL}}},
+	}},
+	{Title: "Support", Entries: Entries{{Title: "docsCloudoguComUrl", Href: "https://docs.cloudogu.com/", Target: TARGET_NEW_WINDOW}}},
+}
+
+func TestRenderHTMLFragment(t *testing.T) {
+	t.Run("should render nested categories", func(t *testing.T) {
+		// when
+		actual, err := RenderHTMLFragment(exportCategories)
+
+		// then
+		require.NoError(t, err)
+		expected := `<nav class="warp-menu" aria-label="Warp menu">
+<ul>
+<li>
+<h2>Development &amp; Co</h2>
+<p>Tools</p>
+<ul>
+<li><a href="/redmine" title="Project &lt;management&gt;">Redmine</a></li>
+</ul>
+<ul>
+<li>
+<h3>CI</h3>
+<ul>
+<li><a href="https://ci.example.com/?a=1&amp;b=2" target="_blank" rel="noopener noreferrer">Builds</a></li>
+</ul>
+</li>
+</ul>
+</li>
+<li>
+<h2>Support</h2>
+<ul>
+<li><a href="https://docs.cloudogu.com/" title="docsCloudoguComUrl" target="_blank" rel="noopener noreferrer">docsCloudoguComUrl</a></li>
+</ul>
+</li>
+</ul>
+</nav>
+`
+		assert.Equal(t, expected, string(actual))
+	})
+
+	t.Run("should not render unsafe links", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Links", Entries: Entries{{DisplayName: "Evil", Href: "javascript:alert(1)"}}}}
+
+		// when
+		actual, err := RenderHTMLFragment(categories)
+
+		// then
+		require.NoError(t, err)
+		assert.NotContains(t, string(actual), "javascript:")
+	})
+}
+
+func TestRenderBookmarks(t *testing.T) {
+	t.Run("should render bookmark file with absolute links", func(t *testing.T) {
+		// when
+		actual, err := RenderBookmarks(exportCategories, "https://ces.example.com/")
+
+		// then
+		require.NoError(t, err)
+		expected := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+<DT><H3>Development &amp; Co</H3>
+<DL><p>
+<DT><A HREF="https://ces.example.com/redmine">Redmine</A>
+<DT><H3>CI</H3>
+<DL><p>
+<DT><A HREF="https://ci.example.com/?a=1&amp;b=2">Builds</A>
+</DL><p>
+</DL><p>
+<DT><H3>Support</H3>
+<DL><p>
+<DT><A HREF="https://docs.cloudogu.com/">docsCloudoguComUrl</A>
+</DL><p>
+</DL><p>
+`
+		assert.Equal(t, expected, string(actual))
+	})
+
+	t.Run("should keep relative links without base url", func(t *testing.T) {
+		// when
+		actual, err := RenderBookmarks(exportCategories, "")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, string(actual), `<A HREF="/redmine">Redmine</A>`)
+	})
+}
@@ -2,21 +2,33 @@ package types
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
 type externalEntry struct {
-	DisplayName string `yaml:"DisplayName"`
-	URL         string `yaml:"URL"`
-	Description string `yaml:"Description"`
-	Category    string `yaml:"Category"`
+	DisplayName string    `yaml:"DisplayName"`
+	URL         string    `yaml:"URL"`
+	Description string    `yaml:"Description"`
+	Category    string    `yaml:"Category"`
+	Order       int       `yaml:"Order"`
+	Target      string    `yaml:"Target"`
+	ValidFrom   time.Time `yaml:"ValidFrom"`
+	ValidUntil  time.Time `yaml:"ValidUntil"`
 }
 
 // EntryWithCategory is a dto for entries with a Category
 type EntryWithCategory struct {
 	Entry    Entry
 	Category string
+	// Tags contains the tags of the dogu the entry was created from.
+	Tags []string
+	// ValidFrom is the time from which the entry is shown. The entry is shown immediately if it is zero.
+	ValidFrom time.Time
+	// ValidUntil is the time from which the entry is no longer shown. The entry is shown indefinitely if it is zero.
+	ValidUntil time.Time
 }
 
 // ExternalConverter is used to read external links from the configuration and convert them to a warp menu category object.
@@ -47,13 +59,28 @@ func mapExternalEntry(entry externalEntry) (EntryWithCategory, error) {
 	if entry.Category == "" {
 		return EntryWithCategory{}, errors.New("could not find Category on external entry")
 	}
+	target := TARGET_EXTERNAL
+	if entry.Target != "" {
+		var err error
+		target, err = ParseTarget(entry.Target)
+		if err != nil {
+			return EntryWithCategory{}, fmt.Errorf("invalid Target on external entry: %w", err)
+		}
+	}
+	if !entry.ValidFrom.IsZero() && !entry.ValidUntil.IsZero() && !entry.ValidUntil.After(entry.ValidFrom) {
+		return EntryWithCategory{}, errors.New("ValidUntil must be after ValidFrom on external entry")
+	}
 	return EntryWithCategory{
 		Entry: Entry{
 			DisplayName: entry.DisplayName,
 			Title:       entry.Description,
 			Href:        entry.URL,
-			Target:      TARGET_EXTERNAL,
+			Target:      target,
+			Source:      SourceExternal,
+			Weight:      entry.Order,
 		},
-		Category: entry.Category,
+		Category:   entry.Category,
+		ValidFrom:  entry.ValidFrom,
+		ValidUntil: entry.ValidUntil,
 	}, nil
 }
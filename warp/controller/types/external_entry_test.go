@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func Test_mapExternalEntry(t *testing.T) {
@@ -26,6 +27,23 @@ func Test_mapExternalEntry(t *testing.T) {
 		assert.Equal(t, externalEntry.Category, entryWithCategory.Category)
 	})
 
+	t.Run("should use order as weight", func(t *testing.T) {
+		// given
+		externalEntry := externalEntry{
+			DisplayName: "HD-Display",
+			URL:         "URL",
+			Category:    "Category",
+			Order:       42,
+		}
+
+		// when
+		entryWithCategory, err := mapExternalEntry(externalEntry)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 42, entryWithCategory.Entry.Weight)
+	})
+
 	t.Run("error because displayname is not set", func(t *testing.T) {
 		// given
 		externalEntry := externalEntry{}
@@ -66,6 +84,58 @@ func Test_mapExternalEntry(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "could not find Category on external entry")
 	})
+
+	t.Run("should use configured target", func(t *testing.T) {
+		// given
+		externalEntry := externalEntry{
+			DisplayName: "HD-Display",
+			URL:         "URL",
+			Category:    "Category",
+			Target:      "embedded",
+		}
+
+		// when
+		entryWithCategory, err := mapExternalEntry(externalEntry)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, TARGET_EMBEDDED, entryWithCategory.Entry.Target)
+	})
+
+	t.Run("error because target is unknown", func(t *testing.T) {
+		// given
+		externalEntry := externalEntry{
+			DisplayName: "HD-Display",
+			URL:         "URL",
+			Category:    "Category",
+			Target:      "popup",
+		}
+
+		// when
+		_, err := mapExternalEntry(externalEntry)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid Target on external entry: unknown target \"popup\"")
+	})
+
+	t.Run("error because validity ends before it starts", func(t *testing.T) {
+		// given
+		externalEntry := externalEntry{
+			DisplayName: "HD-Display",
+			URL:         "URL",
+			Category:    "Category",
+			ValidFrom:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			ValidUntil:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		// when
+		_, err := mapExternalEntry(externalEntry)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ValidUntil must be after ValidFrom on external entry")
+	})
 }
 
 func Test_readAndUnmarshalExternal(t *testing.T) {
@@ -78,6 +148,7 @@ func Test_readAndUnmarshalExternal(t *testing.T) {
 				Href:        "url",
 				Title:       "desc",
 				Target:      TARGET_EXTERNAL,
+				Source:      SourceExternal,
 			},
 			Category: "category",
 		}
@@ -117,4 +188,17 @@ func Test_unmarshalExternal(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to unmarshall external")
 	})
+
+	t.Run("should read validity", func(t *testing.T) {
+		// given
+		externalEntryStr := "DisplayName: Training\nURL: https://training.example.com\nCategory: Links\nValidFrom: 2026-01-01T00:00:00Z\nValidUntil: 2026-02-01T12:00:00+01:00\n"
+
+		// when
+		entryWithCategory, err := unmarshalExternal([]byte(externalEntryStr))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Equal(entryWithCategory.ValidFrom))
+		assert.True(t, time.Date(2026, 2, 1, 11, 0, 0, 0, time.UTC).Equal(entryWithCategory.ValidUntil))
+	})
 }
@@ -1,6 +1,11 @@
 package types
 
-import "github.com/pkg/errors"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
 
 // Entry link in the warp menu
 type Entry struct {
@@ -8,6 +13,15 @@ type Entry struct {
 	Href        string
 	Title       string
 	Target      Target
+	// ID identifies the entry in its source, e.g. the dogu name or the global config key of an external link.
+	ID string `json:"-"`
+	// Source is the kind of source the entry was read from.
+	Source EntrySource `json:"-"`
+	// Weight is used to sort entries in categories with the sort mode "weight".
+	Weight int `json:"-"`
+	// Position is the order in which the entry was read. It is used to sort entries in categories with the sort
+	// mode "source".
+	Position int `json:"-"`
 }
 
 // Target defines the target of the link
@@ -18,21 +32,56 @@ const (
 	TARGET_SELF Target = iota + 1
 	// TARGET_EXTERNAL link is outside from the system
 	TARGET_EXTERNAL
+	// TARGET_NEW_WINDOW link is opened in a new browser window
+	TARGET_NEW_WINDOW
+	// TARGET_EMBEDDED link is embedded in the current page, e.g. in an iframe
+	TARGET_EMBEDDED
 )
 
+var targetNames = map[Target]string{
+	TARGET_SELF:       "self",
+	TARGET_EXTERNAL:   "external",
+	TARGET_NEW_WINDOW: "newWindow",
+	TARGET_EMBEDDED:   "embedded",
+}
+
+// ParseTarget returns the target with the given name, e.g. "self" or "newWindow".
+func ParseTarget(name string) (Target, error) {
+	for target, targetName := range targetNames {
+		if targetName == name {
+			return target, nil
+		}
+	}
+	return 0, errors.Errorf("unknown target %q: must be one of \"self\", \"external\", \"newWindow\", \"embedded\"", name)
+}
+
+func (target Target) String() string {
+	if name, ok := targetNames[target]; ok {
+		return name
+	}
+	return fmt.Sprintf("Target(%d)", uint8(target))
+}
+
 func (target Target) MarshalJSON() ([]byte, error) {
-	switch target {
-	case TARGET_SELF:
-		return target.asJSONString("self"), nil
-	case TARGET_EXTERNAL:
-		return target.asJSONString("external"), nil
-	default:
+	name, ok := targetNames[target]
+	if !ok {
 		return nil, errors.Errorf("unknow target type %d", target)
 	}
+	return json.Marshal(name)
 }
 
-func (target Target) asJSONString(value string) []byte {
-	return []byte("\"" + value + "\"")
+func (target *Target) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.Wrap(err, "target must be a string")
+	}
+
+	parsed, err := ParseTarget(name)
+	if err != nil {
+		return err
+	}
+	*target = parsed
+	return nil
 }
 
 // Entries is a collection of warp entries
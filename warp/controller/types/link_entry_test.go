@@ -16,12 +16,51 @@ func TestCategoryString(t *testing.T) {
 func TestTarget_MarshalJSON(t *testing.T) {
 	testMarshalJSON(t, TARGET_EXTERNAL, "{\"Target\":\"external\"}")
 	testMarshalJSON(t, TARGET_SELF, "{\"Target\":\"self\"}")
+	testMarshalJSON(t, TARGET_NEW_WINDOW, "{\"Target\":\"newWindow\"}")
+	testMarshalJSON(t, TARGET_EMBEDDED, "{\"Target\":\"embedded\"}")
 
 	if _, err := json.Marshal(&targetStruct{12}); err == nil {
 		t.Errorf("marshal should fail because of an invalid value")
 	}
 }
 
+func TestTarget_UnmarshalJSON(t *testing.T) {
+	t.Run("should round trip all targets", func(t *testing.T) {
+		for _, target := range []Target{TARGET_SELF, TARGET_EXTERNAL, TARGET_NEW_WINDOW, TARGET_EMBEDDED} {
+			actual := targetStruct{}
+			err := json.Unmarshal([]byte(marshal(t, target)), &actual)
+
+			assert.NoError(t, err)
+			assert.Equal(t, target, actual.Target)
+		}
+	})
+
+	t.Run("should fail on unknown target", func(t *testing.T) {
+		err := json.Unmarshal([]byte("{\"Target\":\"popup\"}"), &targetStruct{})
+
+		assert.ErrorContains(t, err, "unknown target \"popup\"")
+	})
+
+	t.Run("should fail on non string target", func(t *testing.T) {
+		err := json.Unmarshal([]byte("{\"Target\":1}"), &targetStruct{})
+
+		assert.ErrorContains(t, err, "target must be a string")
+	})
+
+	t.Run("should read categories of a menu", func(t *testing.T) {
+		categories := Categories{}
+		err := json.Unmarshal([]byte(`[{"Title":"Links","Order":0,"Entries":[{"DisplayName":"Docs","Href":"https://docs.cloudogu.com","Title":"","Target":"newWindow"}]}]`), &categories)
+
+		assert.NoError(t, err)
+		assert.Equal(t, TARGET_NEW_WINDOW, categories[0].Entries[0].Target)
+	})
+}
+
+func TestTarget_String(t *testing.T) {
+	assert.Equal(t, "newWindow", TARGET_NEW_WINDOW.String())
+	assert.Equal(t, "Target(12)", Target(12).String())
+}
+
 func TestEntries_Swap(t *testing.T) {
 	// given
 	entry1 := Entry{Title: "1"}
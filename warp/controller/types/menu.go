@@ -0,0 +1,148 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MenuFormat selects the layout of the generated menu.json.
+type MenuFormat string
+
+const (
+	// MenuFormatV1 is the legacy format: a bare array of flattened categories.
+	MenuFormatV1 MenuFormat = "v1"
+	// MenuFormatV2 is an object containing metadata and the nested categories.
+	MenuFormatV2 MenuFormat = "v2"
+	// MenuSchemaVersion is the schema version written in the v2 format.
+	MenuSchemaVersion = 2
+)
+
+// ParseMenuFormat returns the menu format of the given config value. An empty value selects MenuFormatV1.
+func ParseMenuFormat(format string) (MenuFormat, error) {
+	switch MenuFormat(format) {
+	case "", MenuFormatV1:
+		return MenuFormatV1, nil
+	case MenuFormatV2:
+		return MenuFormatV2, nil
+	default:
+		return "", fmt.Errorf("unknown menu format %q: must be one of %q, %q", format, MenuFormatV1, MenuFormatV2)
+	}
+}
+
+// Menu is the v2 representation of the warp menu.
+type Menu struct {
+	SchemaVersion    int            `json:"schemaVersion"`
+	GeneratedAt      time.Time      `json:"generatedAt"`
+	Hash             string         `json:"hash"`
+	GeneratorVersion string         `json:"generatorVersion"`
+	Categories       []MenuCategory `json:"categories"`
+}
+
+// MenuCategory is the v2 representation of a category.
+type MenuCategory struct {
+	Title       string         `json:"title"`
+	Order       int            `json:"order"`
+	Icon        string         `json:"icon,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Collapsed   bool           `json:"collapsed,omitempty"`
+	Entries     []MenuEntry    `json:"entries"`
+	Children    []MenuCategory `json:"children,omitempty"`
+}
+
+// MenuEntry is the v2 representation of an entry.
+type MenuEntry struct {
+	DisplayName string `json:"displayName"`
+	Href        string `json:"href"`
+	Title       string `json:"title"`
+	Target      Target `json:"target"`
+}
+
+// NewMenu creates the v2 menu of the given categories. The hash only covers the categories, so it does not change
+// between generations with the same content.
+func NewMenu(categories Categories, generatedAt time.Time, generatorVersion string) (Menu, error) {
+	menuCategories := toMenuCategories(categories)
+	categoriesJson, err := json.Marshal(menuCategories)
+	if err != nil {
+		return Menu{}, fmt.Errorf("failed to marshal menu categories: %w", err)
+	}
+	hash := sha256.Sum256(categoriesJson)
+
+	return Menu{
+		SchemaVersion:    MenuSchemaVersion,
+		GeneratedAt:      generatedAt.UTC(),
+		Hash:             "sha256:" + hex.EncodeToString(hash[:]),
+		GeneratorVersion: generatorVersion,
+		Categories:       menuCategories,
+	}, nil
+}
+
+func toMenuCategories(categories Categories) []MenuCategory {
+	result := []MenuCategory{}
+	for _, category := range categories {
+		entries := []MenuEntry{}
+		for _, entry := range category.Entries {
+			entries = append(entries, MenuEntry{
+				DisplayName: entry.DisplayName,
+				Href:        entry.Href,
+				Title:       entry.Title,
+				Target:      entry.Target,
+			})
+		}
+
+		menuCategory := MenuCategory{
+			Title:       category.Title,
+			Order:       category.Order,
+			Icon:        category.Icon,
+			Description: category.Description,
+			Collapsed:   category.Collapsed,
+			Entries:     entries,
+		}
+		if len(category.Children) > 0 {
+			menuCategory.Children = toMenuCategories(category.Children)
+		}
+		result = append(result, menuCategory)
+	}
+	return result
+}
+
+// ValidateMenu checks that the generated menu.json can be read again and that all categories and entries are usable
+// by the warp menu.
+func ValidateMenu(data []byte, format MenuFormat) error {
+	if format == MenuFormatV2 {
+		menu := Menu{}
+		if err := json.Unmarshal(data, &menu); err != nil {
+			return fmt.Errorf("failed to unmarshal menu: %w", err)
+		}
+		if menu.SchemaVersion != MenuSchemaVersion {
+			return fmt.Errorf("unexpected schema version %d", menu.SchemaVersion)
+		}
+		return validateMenuCategories(menu.Categories)
+	}
+
+	categories := Categories{}
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return fmt.Errorf("failed to unmarshal menu: %w", err)
+	}
+	return validateMenuCategories(toMenuCategories(categories))
+}
+
+func validateMenuCategories(categories []MenuCategory) error {
+	for _, category := range categories {
+		if category.Title == "" {
+			return errors.New("category without title")
+		}
+		for _, entry := range category.Entries {
+			if entry.Href == "" {
+				return fmt.Errorf("entry %q in category %q has no href", entry.DisplayName, category.Title)
+			}
+		}
+		if err := validateMenuCategories(category.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMenuFormat(t *testing.T) {
+	t.Run("should default to v1", func(t *testing.T) {
+		// when
+		format, err := ParseMenuFormat("")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, MenuFormatV1, format)
+	})
+
+	t.Run("should parse v2", func(t *testing.T) {
+		// when
+		format, err := ParseMenuFormat("v2")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, MenuFormatV2, format)
+	})
+
+	t.Run("should fail on unknown format", func(t *testing.T) {
+		// when
+		_, err := ParseMenuFormat("v3")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown menu format \"v3\"")
+	})
+}
+
+func TestNewMenu(t *testing.T) {
+	categories := Categories{
+		{Title: "Development Apps", Order: 100, Icon: "code", Entries: Entries{
+			{DisplayName: "Redmine", Href: "/redmine", Title: "Project management", Target: TARGET_SELF, ID: "redmine"},
+		}, Children: Categories{
+			{Title: "CI", Collapsed: true, Entries: Entries{{DisplayName: "Jenkins", Href: "/jenkins", Target: TARGET_SELF}}},
+		}},
+	}
+
+	t.Run("should create menu with metadata and nested categories", func(t *testing.T) {
+		// given
+		generatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+		// when
+		menu, err := NewMenu(categories, generatedAt, "1.2.3")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, menu.SchemaVersion)
+		assert.Equal(t, time.Date(2025, 1, 2, 2, 4, 5, 0, time.UTC), menu.GeneratedAt)
+		assert.Equal(t, "1.2.3", menu.GeneratorVersion)
+		assert.Regexp(t, "^sha256:[0-9a-f]{64}$", menu.Hash)
+		require.Len(t, menu.Categories, 1)
+		require.Len(t, menu.Categories[0].Children, 1)
+		assert.Equal(t, "CI", menu.Categories[0].Children[0].Title)
+	})
+
+	t.Run("should marshal camel case fields", func(t *testing.T) {
+		// given
+		menu, err := NewMenu(categories, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), "1.2.3")
+		require.NoError(t, err)
+
+		// when
+		data, err := json.Marshal(menu)
+
+		// then
+		require.NoError(t, err)
+		expected := `{"schemaVersion":2,"generatedAt":"2025-01-02T03:04:05Z","hash":"` + menu.Hash + `","generatorVersion":"1.2.3","categories":[` +
+			`{"title":"Development Apps","order":100,"icon":"code","entries":[{"displayName":"Redmine","href":"/redmine","title":"Project management","target":"self"}],"children":[` +
+			`{"title":"CI","order":0,"collapsed":true,"entries":[{"displayName":"Jenkins","href":"/jenkins","title":"","target":"self"}]}]}]}`
+		assert.JSONEq(t, expected, string(data))
+	})
+
+	t.Run("should only hash the categories", func(t *testing.T) {
+		// when
+		first, err := NewMenu(categories, time.Now(), "1.2.3")
+		require.NoError(t, err)
+		second, err := NewMenu(categories, time.Now().Add(time.Hour), "1.2.4")
+		require.NoError(t, err)
+		other, err := NewMenu(Categories{{Title: "Other", Entries: Entries{}}}, time.Now(), "1.2.3")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, first.Hash, second.Hash)
+		assert.NotEqual(t, first.Hash, other.Hash)
+	})
+}
+
+func TestValidateMenu(t *testing.T) {
+	t.Run("should accept valid menus", func(t *testing.T) {
+		v1 := `[{"Title":"Links","Order":0,"Entries":[{"DisplayName":"Docs","Href":"https://docs.cloudogu.com","Title":"","Target":"external"}]}]`
+		v2 := `{"schemaVersion":2,"categories":[{"title":"Links","order":0,"entries":[],"children":[{"title":"CI","order":0,"entries":[{"displayName":"Jenkins","href":"/jenkins","title":"","target":"self"}]}]}]}`
+
+		assert.NoError(t, ValidateMenu([]byte(v1), MenuFormatV1))
+		assert.NoError(t, ValidateMenu([]byte(v2), MenuFormatV2))
+	})
+
+	t.Run("should reject unreadable menu", func(t *testing.T) {
+		err := ValidateMenu([]byte(`[{"Title":"Links","Entries":[{"Target":"popup"}]}]`), MenuFormatV1)
+
+		assert.ErrorContains(t, err, "failed to unmarshal menu")
+	})
+
+	t.Run("should reject wrong schema version", func(t *testing.T) {
+		err := ValidateMenu([]byte(`{"schemaVersion":1,"categories":[]}`), MenuFormatV2)
+
+		assert.ErrorContains(t, err, "unexpected schema version 1")
+	})
+
+	t.Run("should reject category without title", func(t *testing.T) {
+		err := ValidateMenu([]byte(`{"schemaVersion":2,"categories":[{"title":"Links","entries":[],"children":[{"title":"","entries":[]}]}]}`), MenuFormatV2)
+
+		assert.ErrorContains(t, err, "category without title")
+	})
+
+	t.Run("should reject entry without href", func(t *testing.T) {
+		err := ValidateMenu([]byte(`[{"Title":"Links","Entries":[{"DisplayName":"Docs","Target":"self"}]}]`), MenuFormatV1)
+
+		assert.ErrorContains(t, err, "entry \"Docs\" in category \"Links\" has no href")
+	})
+}
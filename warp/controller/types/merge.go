@@ -0,0 +1,193 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// EntrySource names the kind of source an entry was read from.
+type EntrySource string
+
+const (
+	// SourceDogu marks entries created from a dogu descriptor
+	SourceDogu EntrySource = "dogu"
+	// SourceExternal marks entries created from an external link in the global config
+	SourceExternal EntrySource = "external"
+)
+
+// MergeKey defines which field identifies duplicate entries.
+type MergeKey string
+
+const (
+	// MergeByHref treats entries with the same href as duplicates
+	MergeByHref MergeKey = "href"
+	// MergeByID treats entries with the same id as duplicates
+	MergeByID MergeKey = "id"
+)
+
+// MergePolicy defines how duplicate entries are resolved when categories are merged.
+type MergePolicy struct {
+	Key    MergeKey
+	Prefer EntrySource
+}
+
+// DefaultMergePolicy removes entries with the same href and keeps the entry of the dogu.
+var DefaultMergePolicy = MergePolicy{Key: MergeByHref, Prefer: SourceDogu}
+
+// NewMergePolicy creates a merge policy and validates its values. Empty values are replaced by the values of the
+// DefaultMergePolicy.
+func NewMergePolicy(key string, prefer string) (MergePolicy, error) {
+	policy := DefaultMergePolicy
+	switch MergeKey(key) {
+	case "":
+	case MergeByHref, MergeByID:
+		policy.Key = MergeKey(key)
+	default:
+		return DefaultMergePolicy, fmt.Errorf("unknown merge key %q, use one of [%s, %s]", key, MergeByHref, MergeByID)
+	}
+
+	switch EntrySource(prefer) {
+	case "":
+	case SourceDogu, SourceExternal:
+		policy.Prefer = EntrySource(prefer)
+	default:
+		return DefaultMergePolicy, fmt.Errorf("unknown preferred source %q, use one of [%s, %s]", prefer, SourceDogu, SourceExternal)
+	}
+
+	return policy, nil
+}
+
+func (p MergePolicy) keyOf(entry Entry) string {
+	if p.Key == MergeByID {
+		return entry.ID
+	}
+	return entry.Href
+}
+
+// wins returns true if the entry a should be kept instead of the duplicate entry b. Entries from the preferred source
+// win. Otherwise, the decision only depends on the entries itself and not on the order in which they were read.
+func (p MergePolicy) wins(a, b Entry) bool {
+	if a.Source != b.Source {
+		if a.Source == p.Prefer {
+			return true
+		}
+		if b.Source == p.Prefer {
+			return false
+		}
+	}
+
+	return compareEntries(a, b) < 0
+}
+
+func compareEntries(a, b Entry) int {
+	for _, pair := range [][2]string{
+		{a.ID, b.ID},
+		{a.DisplayName, b.DisplayName},
+		{a.Href, b.Href},
+		{a.Title, b.Title},
+		{string(a.Source), string(b.Source)},
+	} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	for _, pair := range [][2]int{
+		{int(a.Target), int(b.Target)},
+		{a.Weight, b.Weight},
+		{a.Position, b.Position},
+	} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+type entryLocation struct {
+	entry Entry
+	path  string
+}
+
+// deduplicate removes duplicate entries of all categories according to the policy. Categories that lose all entries
+// to a duplicate in another category are removed.
+func (c Categories) deduplicate(policy MergePolicy) Categories {
+	winners := map[string]entryLocation{}
+	c.walkEntries("", func(path string, entry Entry) {
+		key := policy.keyOf(entry)
+		if key == "" {
+			return
+		}
+
+		winner, found := winners[key]
+		if !found || policy.wins(entry, winner.entry) || (entry == winner.entry && path < winner.path) {
+			winners[key] = entryLocation{entry: entry, path: path}
+		}
+	})
+
+	return c.removeLosers("", policy, winners, map[string]bool{})
+}
+
+func (c Categories) walkEntries(parentPath string, visit func(path string, entry Entry)) {
+	for _, category := range c {
+		path := joinCategoryPath(parentPath, category.Title)
+		for _, entry := range category.Entries {
+			visit(path, entry)
+		}
+		category.Children.walkEntries(path, visit)
+	}
+}
+
+func (c Categories) removeLosers(parentPath string, policy MergePolicy, winners map[string]entryLocation, kept map[string]bool) Categories {
+	var result Categories
+	for _, category := range c {
+		path := joinCategoryPath(parentPath, category.Title)
+		hadEntries := len(category.Entries) > 0
+
+		var entries Entries
+		if category.Entries != nil {
+			entries = Entries{}
+		}
+		for _, entry := range category.Entries {
+			key := policy.keyOf(entry)
+			if key == "" {
+				entries = append(entries, entry)
+				continue
+			}
+
+			winner := winners[key]
+			if kept[key] || entry != winner.entry || path != winner.path {
+				continue
+			}
+			kept[key] = true
+			entries = append(entries, entry)
+		}
+		category.Entries = entries
+		category.Children = category.Children.removeLosers(path, policy, winners, kept)
+
+		if hadEntries && len(category.Entries) == 0 && len(category.Children) == 0 {
+			continue
+		}
+		result = append(result, category)
+	}
+	return result
+}
+
+func joinCategoryPath(parentPath string, title string) string {
+	if parentPath == "" {
+		return title
+	}
+	return parentPath + CategoryPathSeparator + title
+}
+
+// sortEntries sorts the entries of the category with a stable sort, so entries which are equal for the sort order
+// keep the order in which they were merged.
+func (c *Category) sortEntries() {
+	sort.Stable(c.Entries)
+}
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMergePolicy(t *testing.T) {
+	t.Run("should use default for empty values", func(t *testing.T) {
+		// when
+		policy, err := NewMergePolicy("", "")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, DefaultMergePolicy, policy)
+	})
+
+	t.Run("should create policy", func(t *testing.T) {
+		// when
+		policy, err := NewMergePolicy("id", "external")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, MergePolicy{Key: MergeByID, Prefer: SourceExternal}, policy)
+	})
+
+	t.Run("should fail on unknown key", func(t *testing.T) {
+		// when
+		_, err := NewMergePolicy("name", "")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown merge key \"name\"")
+	})
+
+	t.Run("should fail on unknown source", func(t *testing.T) {
+		// when
+		_, err := NewMergePolicy("", "support")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown preferred source \"support\"")
+	})
+}
+
+func TestCategories_InsertCategoryWithPolicy(t *testing.T) {
+	doguEntry := Entry{DisplayName: "Redmine", Href: "/redmine", Target: TARGET_SELF, ID: "redmine", Source: SourceDogu}
+	externalEntry := Entry{DisplayName: "Redmine", Href: "/redmine", Target: TARGET_EXTERNAL, ID: "redmine", Source: SourceExternal}
+	otherExternalEntry := Entry{DisplayName: "Cloudogu", Href: "https://cloudogu.com", Target: TARGET_EXTERNAL, ID: "cloudogu", Source: SourceExternal}
+
+	t.Run("should keep preferred dogu entry and remove emptied category", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Links", Entries: Entries{externalEntry}}}
+
+		// when
+		categories.InsertCategoryWithPolicy(&Category{Title: "Development Apps", Entries: Entries{doguEntry}}, DefaultMergePolicy)
+
+		// then
+		expected := Categories{{Title: "Development Apps", Entries: Entries{doguEntry}}}
+		assert.Equal(t, expected, categories)
+	})
+
+	t.Run("should keep preferred external entry", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Links", Entries: Entries{externalEntry, otherExternalEntry}}}
+		policy := MergePolicy{Key: MergeByHref, Prefer: SourceExternal}
+
+		// when
+		categories.InsertCategoryWithPolicy(&Category{Title: "Development Apps", Entries: Entries{doguEntry}}, policy)
+
+		// then
+		expected := Categories{{Title: "Links", Entries: Entries{externalEntry, otherExternalEntry}}}
+		assert.Equal(t, expected, categories)
+	})
+
+	t.Run("should merge by id", func(t *testing.T) {
+		// given
+		external := Entry{DisplayName: "Redmine", Href: "https://redmine.example.com", ID: "redmine", Source: SourceExternal}
+		categories := Categories{{Title: "Links", Entries: Entries{external}}}
+		policy := MergePolicy{Key: MergeByID, Prefer: SourceDogu}
+
+		// when
+		categories.InsertCategoryWithPolicy(&Category{Title: "Development Apps", Entries: Entries{doguEntry}}, policy)
+
+		// then
+		expected := Categories{{Title: "Development Apps", Entries: Entries{doguEntry}}}
+		assert.Equal(t, expected, categories)
+	})
+
+	t.Run("should keep highest order of merged categories", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Links", Order: 10, Entries: Entries{otherExternalEntry}}}
+
+		// when
+		categories.InsertCategoryWithPolicy(&Category{Title: "Links", Order: 20, Entries: Entries{externalEntry}}, DefaultMergePolicy)
+
+		// then
+		require.Equal(t, 1, len(categories))
+		assert.Equal(t, 20, categories[0].Order)
+		assert.Equal(t, Entries{otherExternalEntry, externalEntry}, categories[0].Entries)
+	})
+
+	t.Run("should create identical result regardless of insert order", func(t *testing.T) {
+		// given
+		duplicateA := Entry{DisplayName: "Docs A", Href: "https://docs.example.com", Target: TARGET_EXTERNAL, ID: "a", Source: SourceExternal}
+		duplicateB := Entry{DisplayName: "Docs B", Href: "https://docs.example.com", Target: TARGET_EXTERNAL, ID: "b", Source: SourceExternal}
+		newCategories := func() Categories {
+			return Categories{
+				{Title: "Links", Order: 10, Entries: Entries{duplicateB, otherExternalEntry}},
+				{Title: "Development Apps", Entries: Entries{doguEntry}},
+				{Title: "Links", Order: 20, Entries: Entries{externalEntry, duplicateA}},
+			}
+		}
+		forward := Categories{}
+		backward := Categories{}
+
+		// when
+		toInsert := newCategories()
+		for i := range toInsert {
+			forward.InsertCategory(toInsert[i])
+		}
+		toInsert = newCategories()
+		for i := len(toInsert) - 1; i >= 0; i-- {
+			backward.InsertCategory(toInsert[i])
+		}
+		forward.SortRecursive()
+		backward.SortRecursive()
+
+		// then
+		forwardJson, err := json.Marshal(forward)
+		require.NoError(t, err)
+		backwardJson, err := json.Marshal(backward)
+		require.NoError(t, err)
+		assert.Equal(t, string(forwardJson), string(backwardJson))
+		assert.Equal(t, "Links", forward[0].Title)
+		assert.Equal(t, Entries{otherExternalEntry, duplicateA}, forward[0].Entries)
+	})
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// SortMode defines the order of the entries in a category.
+type SortMode string
+
+const (
+	// SortAlphabetical sorts entries by their display name with the collation of the configured locale
+	SortAlphabetical SortMode = "alphabetical"
+	// SortWeight sorts entries by their weight, entries with a higher weight come first
+	SortWeight SortMode = "weight"
+	// SortSource keeps the order in which the entries were read from their sources
+	SortSource SortMode = "source"
+)
+
+// Sorting sorts categories and their entries.
+type Sorting struct {
+	locale        language.Tag
+	defaultMode   SortMode
+	categoryModes map[string]SortMode
+}
+
+// NewSorting creates a sorting for the given locale, e.g. "de". The default mode is used for all categories without
+// an explicit mode. Category modes are configured by the category title or path. Empty values default to the root
+// locale and alphabetical sorting.
+func NewSorting(locale string, defaultMode string, categoryModes map[string]string) (Sorting, error) {
+	sorting := Sorting{locale: language.Und, defaultMode: SortAlphabetical, categoryModes: map[string]SortMode{}}
+
+	if locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return Sorting{}, fmt.Errorf("failed to parse locale %q: %w", locale, err)
+		}
+		sorting.locale = tag
+	}
+
+	if defaultMode != "" {
+		mode, err := parseSortMode(defaultMode)
+		if err != nil {
+			return Sorting{}, err
+		}
+		sorting.defaultMode = mode
+	}
+
+	for category, value := range categoryModes {
+		mode, err := parseSortMode(value)
+		if err != nil {
+			return Sorting{}, fmt.Errorf("invalid sort mode for category %q: %w", category, err)
+		}
+		sorting.categoryModes[category] = mode
+	}
+
+	return sorting, nil
+}
+
+// DefaultSorting sorts alphabetically with the collation of the root locale.
+func DefaultSorting() Sorting {
+	return Sorting{locale: language.Und, defaultMode: SortAlphabetical, categoryModes: map[string]SortMode{}}
+}
+
+func parseSortMode(value string) (SortMode, error) {
+	switch mode := SortMode(strings.ToLower(value)); mode {
+	case SortAlphabetical, SortWeight, SortSource:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown sort mode %q, use one of [%s, %s, %s]", value, SortAlphabetical, SortWeight, SortSource)
+	}
+}
+
+// Sort sorts the categories, their entries and all child categories. Categories are sorted by their order and
+// title, entries by the mode of their category.
+func (s Sorting) Sort(categories Categories) {
+	collator := collate.New(s.locale)
+	s.sort(collator, categories, "")
+}
+
+func (s Sorting) sort(collator *collate.Collator, categories Categories, parentPath string) {
+	for _, category := range categories {
+		path := joinCategoryPath(parentPath, category.Title)
+		s.sortEntries(collator, category.Entries, s.modeOf(path))
+		s.sort(collator, category.Children, path)
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].Order != categories[j].Order {
+			return categories[i].Order > categories[j].Order
+		}
+		return compareStrings(collator, categories[i].Title, categories[j].Title) < 0
+	})
+}
+
+func (s Sorting) modeOf(categoryPath string) SortMode {
+	if mode, ok := s.categoryModes[categoryPath]; ok {
+		return mode
+	}
+	return s.defaultMode
+}
+
+func (s Sorting) sortEntries(collator *collate.Collator, entries Entries, mode SortMode) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch mode {
+		case SortSource:
+			return a.Position < b.Position
+		case SortWeight:
+			if a.Weight != b.Weight {
+				return a.Weight > b.Weight
+			}
+		}
+		return compareStrings(collator, a.DisplayName, b.DisplayName) < 0
+	})
+}
+
+// compareStrings compares with the collator and falls back to the byte order for strings the collator treats as
+// equal, so the result is always deterministic.
+func compareStrings(collator *collate.Collator, a, b string) int {
+	if result := collator.CompareString(a, b); result != 0 {
+		return result
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func displayNames(entries Entries) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.DisplayName)
+	}
+	return names
+}
+
+func TestNewSorting(t *testing.T) {
+	t.Run("should fail on invalid locale", func(t *testing.T) {
+		// when
+		_, err := NewSorting("not a locale!", "", nil)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse locale \"not a locale!\"")
+	})
+
+	t.Run("should fail on unknown default mode", func(t *testing.T) {
+		// when
+		_, err := NewSorting("", "random", nil)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown sort mode \"random\"")
+	})
+
+	t.Run("should fail on unknown category mode", func(t *testing.T) {
+		// when
+		_, err := NewSorting("de", "", map[string]string{"Support": "random"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid sort mode for category \"Support\"")
+	})
+}
+
+func TestSorting_Sort(t *testing.T) {
+	t.Run("should sort alphabetically with collation", func(t *testing.T) {
+		// given
+		sorting, err := NewSorting("de", "", nil)
+		require.NoError(t, err)
+		categories := Categories{{Title: "Apps", Entries: Entries{
+			{DisplayName: "Zammad"},
+			{DisplayName: "Übersicht"},
+			{DisplayName: "admin"},
+			{DisplayName: "Redmine"},
+		}}}
+
+		// when
+		sorting.Sort(categories)
+
+		// then
+		assert.Equal(t, []string{"admin", "Redmine", "Übersicht", "Zammad"}, displayNames(categories[0].Entries))
+	})
+
+	t.Run("should sort by weight and name", func(t *testing.T) {
+		// given
+		sorting, err := NewSorting("", "weight", nil)
+		require.NoError(t, err)
+		categories := Categories{{Title: "Apps", Entries: Entries{
+			{DisplayName: "A"},
+			{DisplayName: "C", Weight: 10},
+			{DisplayName: "B", Weight: 10},
+			{DisplayName: "D", Weight: 20},
+		}}}
+
+		// when
+		sorting.Sort(categories)
+
+		// then
+		assert.Equal(t, []string{"D", "B", "C", "A"}, displayNames(categories[0].Entries))
+	})
+
+	t.Run("should use mode of category path", func(t *testing.T) {
+		// given
+		sorting, err := NewSorting("", "", map[string]string{"Apps/CI": "source"})
+		require.NoError(t, err)
+		entries := func() Entries {
+			return Entries{{DisplayName: "B", Position: 1}, {DisplayName: "A", Position: 2}}
+		}
+		categories := Categories{{Title: "Apps", Entries: entries(), Children: Categories{{Title: "CI", Entries: entries()}}}}
+
+		// when
+		sorting.Sort(categories)
+
+		// then
+		assert.Equal(t, []string{"A", "B"}, displayNames(categories[0].Entries))
+		assert.Equal(t, []string{"B", "A"}, displayNames(categories[0].Children[0].Entries))
+	})
+
+	t.Run("should sort categories by order and title", func(t *testing.T) {
+		// given
+		categories := Categories{{Title: "Zubehör"}, {Title: "Ärzte"}, {Title: "Support", Order: 10}}
+
+		// when
+		DefaultSorting().Sort(categories)
+
+		// then
+		assert.Equal(t, "Support", categories[0].Title)
+		assert.Equal(t, "Ärzte", categories[1].Title)
+		assert.Equal(t, "Zubehör", categories[2].Title)
+	})
+}
@@ -2,10 +2,10 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/cloudogu/warp-assets/config"
 	"github.com/cloudogu/warp-assets/controller/types"
@@ -13,40 +13,81 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	types2 "k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
+	menuJsonFileName                 = "menu.json"
+	menuLastGoodFileName             = "menu.last-good.json"
+	menuHtmlFileName                 = "menu.html"
+	menuBookmarksFileName            = "bookmarks.html"
+	fqdnGlobalConfigKey              = "fqdn"
 	globalConfigMapName              = "global-config"
 	warpMenuUpdateEventReason        = "WarpMenu"
 	errorOnWarpMenuUpdateEventReason = "ErrUpdateWarpMenu"
+	rejectedWarpMenuEntryEventReason = "RejectedWarpMenuEntry"
+	shrinkGuardEventReason           = "WarpMenuShrinkGuard"
+	templateErrorEventReason         = "WarpMenuTemplateError"
+	// minEntryChangeRequeueDelay is the shortest delay before the warp menu is generated again for a time-limited
+	// entry.
+	minEntryChangeRequeueDelay = time.Second
 )
 
 type WarpMenuConfigReconciler struct {
 	client              k8sClient
 	globalConfigRepo    GlobalConfigRepository
 	doguVersionRegistry DoguVersionRegistry
-	localDoguRepo       LocalDoguRepo
 	eventRecorder       eventRecorder
 	warpMenuPath        string
 	deploymentName      string
+	generatorVersion    string
+	sourceCache         *SourceCache
+	sourceCacheLoaded   bool
+	sourceReaders       *SourceReaderRegistry
+	// shrinkDetectedAt contains the time at which the shrink guard first rejected the current shrink by namespace.
+	shrinkDetectedAt map[string]time.Time
+	// reportedTemplateErrors contains the template errors of the last reconcile by namespace. Only new errors are
+	// recorded as events.
+	reportedTemplateErrors map[string][]TemplateError
+	// reportedRejectedEntries contains the rejected entries of the last reconcile by namespace. Only new rejections are
+	// recorded as events.
+	reportedRejectedEntries map[string][]RejectedEntry
+	// menuServer serves the generated menus over HTTP. It is nil if the menu is only served by nginx.
+	menuServer *MenuServer
+	// relevantTriggers contains the changes that affect the warp menu. It is nil until the warp config was read.
+	relevantTriggers      *SourceWatchTriggers
+	relevantTriggersMutex sync.Mutex
 }
 
-func NewWarpMenuReconciler(client k8sClient, globalConfigRepo GlobalConfigRepository, doguVersionRegistry DoguVersionRegistry, localDoguRepo LocalDoguRepo, eventRecoder eventRecorder, warpMenuPath string, deploymentName string) *WarpMenuConfigReconciler {
+func NewWarpMenuReconciler(client k8sClient, globalConfigRepo GlobalConfigRepository, doguVersionRegistry DoguVersionRegistry, localDoguRepo LocalDoguRepo, eventRecoder eventRecorder, warpMenuPath string, deploymentName string, generatorVersion string) *WarpMenuConfigReconciler {
 	return &WarpMenuConfigReconciler{
 		client:              client,
 		globalConfigRepo:    globalConfigRepo,
 		doguVersionRegistry: doguVersionRegistry,
-		localDoguRepo:       localDoguRepo,
 		eventRecorder:       eventRecoder,
 		warpMenuPath:        warpMenuPath,
 		deploymentName:      deploymentName,
+		generatorVersion:    generatorVersion,
+		sourceCache:         NewSourceCache(),
+		sourceReaders: NewSourceReaderRegistry(
+			NewDogusSourceReader(doguVersionRegistry, localDoguRepo, NewDoguEntryCache(DefaultDoguEntryCacheSize)),
+			NewExternalsSourceReader(),
+		),
 	}
 }
 
+// SourceReaders returns the registry of the source readers. Readers for additional source types can be registered
+// before the manager is started.
+func (r *WarpMenuConfigReconciler) SourceReaders() *SourceReaderRegistry {
+	return r.sourceReaders
+}
+
+// SetMenuServer publishes all generated menus to the given server.
+func (r *WarpMenuConfigReconciler) SetMenuServer(menuServer *MenuServer) {
+	r.menuServer = menuServer
+}
+
 func (r *WarpMenuConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("WarpMenuConfigReconciler reconcile()")
@@ -63,81 +104,186 @@ func (r *WarpMenuConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, fmt.Errorf("read warp menu configuration: %w", err)
 	}
 
-	categories, err := r.createCategories(ctx, warpMenuConfiguration)
+	if warpMenuConfiguration.SourceCache.Persist {
+		if err = r.loadSourceCache(ctx, req.Namespace); err != nil {
+			r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Loading warp menu source cache failed: %v", err)
+			return ctrl.Result{}, fmt.Errorf("load source cache: %w", err)
+		}
+	}
+
+	r.setRelevantWatchTriggers(relevantWatchTriggers(warpMenuConfiguration, r.sourceReaders))
+
+	categories, hostCategories, status, err := r.createCategories(ctx, warpMenuConfiguration)
 	if err != nil {
 		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Creating warp menu categories failed: %w", err)
 		return ctrl.Result{}, fmt.Errorf("create categories: %w", err)
 	}
 
-	err = r.writeWarpMenuFile(categories)
+	if warpMenuConfiguration.SourceCache.Persist {
+		if err = r.persistSourceCache(ctx, req.Namespace); err != nil {
+			r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Writing warp menu source cache failed: %v", err)
+			return ctrl.Result{}, fmt.Errorf("persist source cache: %w", err)
+		}
+	}
+
+	r.recordRejectedEntries(deployment, req.Namespace, status.RejectedEntries)
+	r.recordTemplateErrors(deployment, req.Namespace, status.TemplateErrors)
+
+	err = r.checkShrinkGuard(ctx, req.Namespace, warpMenuConfiguration.ShrinkGuard, status.EntryCounts)
+	if err != nil {
+		// returning the error keeps the previous menu and retries with backoff
+		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, shrinkGuardEventReason, "Kept previous warp menu: %s", err.Error())
+		return ctrl.Result{}, fmt.Errorf("shrink guard: %w", err)
+	}
+
+	err = r.writeWarpMenuFile(categories, warpMenuConfiguration.MenuFormat, warpMenuConfiguration.Compression)
 	if err != nil {
 		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Writing warp menu file failed: %w", err)
 		return ctrl.Result{}, fmt.Errorf("write warp menu file: %w", err)
 	}
 
+	err = r.writeHostMenuFiles(hostCategories, warpMenuConfiguration.MenuFormat, warpMenuConfiguration.Compression)
+	if err != nil {
+		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Writing host warp menu files failed: %v", err)
+		return ctrl.Result{}, fmt.Errorf("write host warp menu files: %w", err)
+	}
+
+	if r.menuServer != nil {
+		if err = r.publishMenus(hostCategories); err != nil {
+			r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Publishing warp menu failed: %v", err)
+			return ctrl.Result{}, fmt.Errorf("publish warp menu: %w", err)
+		}
+	}
+
+	err = r.writeExportFiles(ctx, categories)
+	if err != nil {
+		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Writing warp menu export files failed: %v", err)
+		return ctrl.Result{}, fmt.Errorf("write warp menu export files: %w", err)
+	}
+
+	err = r.writeStatus(ctx, req.Namespace, status)
+	if err != nil {
+		r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, errorOnWarpMenuUpdateEventReason, "Writing warp menu status failed: %v", err)
+		return ctrl.Result{}, fmt.Errorf("write warp menu status: %w", err)
+	}
+
 	r.eventRecorder.Event(deployment, corev1.EventTypeNormal, warpMenuUpdateEventReason, "Warp menu updated.")
-	return ctrl.Result{}, nil
+	return requeueAtNextEntryChange(status.NextEntryChange), nil
 }
 
-func (r *WarpMenuConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.ConfigMap{}).
-		WithEventFilter(eventFilterPredicate()).
-		Complete(r)
+// requeueAtNextEntryChange generates the warp menu again when the next time-limited entry appears or disappears.
+func requeueAtNextEntryChange(nextEntryChange *time.Time) ctrl.Result {
+	if nextEntryChange == nil {
+		return ctrl.Result{}
+	}
+	// a zero RequeueAfter would not requeue at all, so a change that just passed is requeued after a short delay
+	return ctrl.Result{RequeueAfter: max(time.Until(*nextEntryChange), minEntryChangeRequeueDelay)}
 }
 
-func eventFilterPredicate() predicate.Predicate {
-	return predicate.Funcs{
-		CreateFunc: func(e event.TypedCreateEvent[client.Object]) bool {
-			return isWatchedConfigMap(e.Object.GetName())
-		},
-		DeleteFunc: func(e event.TypedDeleteEvent[client.Object]) bool {
-			return isWatchedConfigMap(e.Object.GetName())
-		},
-		UpdateFunc: func(e event.TypedUpdateEvent[client.Object]) bool {
-			return isWatchedConfigMap(e.ObjectOld.GetName())
-		},
-		GenericFunc: func(e event.TypedGenericEvent[client.Object]) bool {
-			return isWatchedConfigMap(e.Object.GetName())
-		},
+// SetupWithManager registers the reconciler. Changes of the warp config, the current dogu versions and the global
+// config of the namespace trigger the same request, which is debounced with the given window and max delay.
+func (r *WarpMenuConfigReconciler) SetupWithManager(mgr ctrl.Manager, namespace string, debounceWindow time.Duration, debounceMaxDelay time.Duration) error {
+	watcher := newRegistryWatcher(namespace, r.doguVersionRegistry, r.globalConfigRepo, r.relevantGlobalConfigKeys, r.doguVersionsRelevant)
+	if err := mgr.Add(watcher); err != nil {
+		return fmt.Errorf("failed to add registry watcher: %w", err)
 	}
-}
 
-func isWatchedConfigMap(configMapName string) bool {
-	isDoguSpecConfigMap := strings.HasPrefix(configMapName, "dogu-spec-")
-	return isDoguSpecConfigMap || configMapName == globalConfigMapName || configMapName == config.WarpConfigMap
+	eventHandler := newDebouncedEventHandler(debounceWindow, debounceMaxDelay)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("warpmenu").
+		Watches(&corev1.ConfigMap{}, eventHandler).
+		WatchesRawSource(source.Channel(watcher.events, eventHandler)).
+		WithEventFilter(eventFilterPredicate()).
+		Complete(r)
 }
 
-func (r *WarpMenuConfigReconciler) createCategories(ctx context.Context, warpMenuConfiguration *config.Configuration) (types.Categories, error) {
+// createCategories returns the categories of the default menu and of the menus of the configured hosts.
+func (r *WarpMenuConfigReconciler) createCategories(ctx context.Context, warpMenuConfiguration *config.Configuration) (types.Categories, map[string]types.Categories, WarpMenuStatus, error) {
 	configReader := NewConfigReader(
 		warpMenuConfiguration,
 		r.globalConfigRepo,
-		r.doguVersionRegistry,
-		r.localDoguRepo,
+		r.sourceReaders,
+		r.sourceCache,
 	)
 
-	return configReader.Read(ctx, warpMenuConfiguration)
+	categories, err := configReader.Read(ctx, warpMenuConfiguration)
+	if err != nil {
+		return nil, nil, WarpMenuStatus{}, err
+	}
+
+	status := WarpMenuStatus{
+		RejectedEntries: configReader.RejectedEntries(),
+		EntryCounts:     configReader.EntryCounts(),
+		StaleSources:    configReader.StaleSources(),
+		TemplateErrors:  configReader.TemplateErrors(),
+	}
+	if nextEntryChange := configReader.NextEntryChange(); !nextEntryChange.IsZero() {
+		status.NextEntryChange = &nextEntryChange
+	}
+	return categories, configReader.HostCategories(), status, nil
 }
 
-func (r *WarpMenuConfigReconciler) writeWarpMenuFile(categories types.Categories) error {
-	jsonData, err := json.Marshal(categories)
+// writeExportFiles writes the categories as HTML fragment for clients without JavaScript and as bookmark file.
+func (r *WarpMenuConfigReconciler) writeExportFiles(ctx context.Context, categories types.Categories) error {
+	htmlData, err := types.RenderHTMLFragment(categories)
 	if err != nil {
-		return fmt.Errorf("failed to marshal warp data: %w", err)
+		return err
+	}
+	if err = r.writeFile(menuHtmlFileName, htmlData); err != nil {
+		return err
 	}
 
-	path := r.warpMenuPath + "/menu.json"
-	file, err := os.Create(path)
+	bookmarkData, err := types.RenderBookmarks(categories, r.readBaseURL(ctx))
 	if err != nil {
-		return fmt.Errorf("failed to create file: %s %w", path, err)
+		return err
 	}
-	defer func() {
-		_ = file.Close()
-	}()
+	return r.writeFile(menuBookmarksFileName, bookmarkData)
+}
 
-	_, err = file.WriteString(string(jsonData))
+// readBaseURL returns the url of the ecosystem used for relative links in the bookmark file. An empty string is
+// returned if the fqdn is not available.
+func (r *WarpMenuConfigReconciler) readBaseURL(ctx context.Context) string {
+	globalConfig, err := r.globalConfigRepo.Get(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to write json data: %w", err)
+		ctrl.Log.Info(fmt.Sprintf("failed to read fqdn for warp menu bookmarks: %s", err.Error()))
+		return ""
+	}
+
+	fqdn, exists := globalConfig.Get(fqdnGlobalConfigKey)
+	if !exists || fqdn.String() == "" {
+		return ""
+	}
+	return "https://" + fqdn.String()
+}
+
+// recordRejectedEntries records a warning event for every rejected entry that was not rejected in the last reconcile,
+// so an unchanged rejection does not create an event on every generation.
+func (r *WarpMenuConfigReconciler) recordRejectedEntries(deployment *appsv1.Deployment, namespace string, rejectedEntries []RejectedEntry) {
+	if r.reportedRejectedEntries == nil {
+		r.reportedRejectedEntries = map[string][]RejectedEntry{}
 	}
 
-	return nil
+	reported := r.reportedRejectedEntries[namespace]
+	for _, rejected := range rejectedEntries {
+		if !slices.Contains(reported, rejected) {
+			r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, rejectedWarpMenuEntryEventReason, "Global config key %q was skipped for the warp menu: %s", rejected.Key, rejected.Reason)
+		}
+	}
+	r.reportedRejectedEntries[namespace] = rejectedEntries
+}
+
+// recordTemplateErrors records a warning event for every template error that did not occur in the last reconcile, so
+// an unchanged error does not create an event on every generation.
+func (r *WarpMenuConfigReconciler) recordTemplateErrors(deployment *appsv1.Deployment, namespace string, templateErrors []TemplateError) {
+	if r.reportedTemplateErrors == nil {
+		r.reportedTemplateErrors = map[string][]TemplateError{}
+	}
+
+	reported := r.reportedTemplateErrors[namespace]
+	for _, templateErr := range templateErrors {
+		if !slices.Contains(reported, templateErr) {
+			r.eventRecorder.Eventf(deployment, corev1.EventTypeWarning, templateErrorEventReason, "Entry %q of source %s was skipped for the warp menu: %s", templateErr.Entry, templateErr.Source, templateErr.Reason)
+		}
+	}
+	r.reportedTemplateErrors[namespace] = templateErrors
 }
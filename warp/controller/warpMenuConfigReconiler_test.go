@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudogu/ces-commons-lib/dogu"
 	"github.com/cloudogu/cesapp-lib/core"
@@ -15,8 +16,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	types2 "k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,8 +30,9 @@ import (
 )
 
 const (
-	testDeploymentName = "aDeployment"
-	testNamespace      = "aNamespace"
+	testDeploymentName   = "aDeployment"
+	testNamespace        = "aNamespace"
+	testGeneratorVersion = "1.2.3"
 )
 
 func TestWarpMenuEventFilterPredicate(t *testing.T) {
@@ -50,9 +55,10 @@ func TestWarpMenuEventFilterPredicate(t *testing.T) {
 		})
 	}
 
-	checkEventFilterPredicate("dogu-spec-redmine", true)
-	checkEventFilterPredicate("dogu-spec-postgres", true)
-	checkEventFilterPredicate(globalConfigMapName, true)
+	// dogu specs and the global config are watched through the registry
+	checkEventFilterPredicate("dogu-spec-redmine", false)
+	checkEventFilterPredicate("dogu-spec-postgres", false)
+	checkEventFilterPredicate(globalConfigMapName, false)
 	checkEventFilterPredicate(config.WarpConfigMap, true)
 	checkEventFilterPredicate("a-config-map", false)
 }
@@ -68,6 +74,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		warpMenuPath := t.TempDir()
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Sources: []config.Source{
@@ -89,7 +96,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		})
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -99,6 +106,13 @@ func TestWarpMenuReconcile(t *testing.T) {
 		assert.Equal(t, 1, len(warpMenuCategories))
 		assert.Equal(t, "News", warpMenuCategories[0].Title)
 
+		htmlData, err := os.ReadFile(warpMenuPath + "/menu.html")
+		require.NoError(t, err)
+		assert.Contains(t, string(htmlData), `<a href="https://test.example.com" title="Daily Tech News" target="_blank" rel="noopener noreferrer">Test</a>`)
+		bookmarkData, err := os.ReadFile(warpMenuPath + "/bookmarks.html")
+		require.NoError(t, err)
+		assert.Contains(t, string(bookmarkData), `<DT><A HREF="https://test.example.com">Test</A>`)
+
 		expectedWarpMenuEntries := []WarpMenuEntry{
 			{
 				Title:       "Daily Tech News",
@@ -110,6 +124,303 @@ func TestWarpMenuReconcile(t *testing.T) {
 		assert.ElementsMatch(t, expectedWarpMenuEntries, warpMenuCategories[0].Entries)
 	})
 
+	t.Run("should write menu in format v2", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		localDoguRepo := NewMockLocalDoguRepo(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+
+		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
+
+		warpMenuConfig := config.Configuration{
+			Sources: []config.Source{
+				{
+					Path: "externals",
+					Type: "externals",
+				},
+			},
+			MenuFormat:       "v2",
+			NestedCategories: true,
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+
+		globalConfig := config2.CreateGlobalConfig(config2.Entries{
+			"externals/myentry": config2.Value(multiline(
+				`DisplayName: Test`,
+				`URL: "https://test.example.com"`,
+				`Category: News/Tech`,
+			)),
+		})
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
+
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(warpMenuPath + "/menu.json")
+		require.NoError(t, err)
+		menu := map[string]any{}
+		require.NoError(t, json.Unmarshal(data, &menu))
+		assert.Equal(t, float64(2), menu["schemaVersion"])
+		assert.Equal(t, testGeneratorVersion, menu["generatorVersion"])
+		assert.NotEmpty(t, menu["generatedAt"])
+		assert.NotEmpty(t, menu["hash"])
+		categories := menu["categories"].([]any)
+		require.Len(t, categories, 1)
+		news := categories[0].(map[string]any)
+		assert.Equal(t, "News", news["title"])
+		tech := news["children"].([]any)[0].(map[string]any)
+		assert.Equal(t, "Tech", tech["title"])
+		assert.Equal(t, "Test", tech["entries"].([]any)[0].(map[string]any)["displayName"])
+	})
+
+	t.Run("should keep previous menu if a source is empty", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		localDoguRepo := NewMockLocalDoguRepo(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+		require.NoError(t, os.WriteFile(warpMenuPath+"/menu.json", []byte("previous"), 0644))
+
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: testDeploymentName, Namespace: testNamespace}, mock.AnythingOfType("*v1.Deployment")).
+			Return(nil)
+		warpMenuConfig := config.Configuration{
+			Sources: []config.Source{
+				{
+					Path: "externals",
+					Type: "externals",
+				},
+			},
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"externals:externals":3}}`}
+			}).
+			Return(nil)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config2.GlobalConfig{}, assert.AnError)
+		eventRecorderMock.EXPECT().Eventf(mock.Anything, v1.EventTypeWarning, shrinkGuardEventReason, "Kept previous warp menu: %s", mock.MatchedBy(func(message string) bool {
+			return strings.HasPrefix(message, "source \"externals:externals\" contained 3 entries before and is empty now; accepted if it persists until ")
+		}))
+
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "shrink guard")
+
+		data, err := os.ReadFile(warpMenuPath + "/menu.json")
+		require.NoError(t, err)
+		assert.Equal(t, "previous", string(data))
+	})
+
+	t.Run("should write menu if a shrink persists for the grace period", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+		require.NoError(t, os.WriteFile(warpMenuPath+"/menu.json", []byte("previous"), 0644))
+
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: testDeploymentName, Namespace: testNamespace}, mock.AnythingOfType("*v1.Deployment")).
+			Return(nil)
+		warpMenuConfig := config.Configuration{
+			Sources:     []config.Source{{Path: "externals", Type: "externals"}},
+			Support:     []config.SupportSource{{Identifier: "docsCloudoguComUrl", External: true, Href: "https://docs.cloudogu.com/"}},
+			ShrinkGuard: config.ShrinkGuardConfig{GracePeriod: "1h"},
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"externals:externals":1}}`}
+			}).
+			Return(nil)
+		// the last external link was deleted on purpose
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config2.CreateGlobalConfig(config2.Entries{}), nil)
+		eventRecorderMock.EXPECT().Eventf(mock.Anything, v1.EventTypeWarning, shrinkGuardEventReason, "Kept previous warp menu: %s", mock.Anything).Once()
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, NewMockDoguVersionRegistry(t), NewMockLocalDoguRepo(t), eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.Error(t, err)
+		data, err := os.ReadFile(warpMenuPath + "/menu.json")
+		require.NoError(t, err)
+		assert.Equal(t, "previous", string(data))
+
+		// the retry after the grace period writes the smaller menu
+		reconciler.shrinkDetectedAt[testNamespace] = time.Now().Add(-time.Hour)
+		clientMock.EXPECT().Update(mock.Anything, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) {
+				assert.JSONEq(t, `{"rejectedEntries":[],"entryCounts":{"externals:externals":0}}`, obj.(*v1.ConfigMap).Data[warpMenuStatusDataKey])
+			}).
+			Return(nil)
+		eventRecorderMock.EXPECT().Event(mock.Anything, v1.EventTypeNormal, warpMenuUpdateEventReason, "Warp menu updated.")
+
+		_, err = reconciler.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		warpMenuCategories := parseWarpMenuCategoriesFromJsonFile(t, warpMenuPath)
+		require.Len(t, warpMenuCategories, 1)
+		assert.Equal(t, "Support", warpMenuCategories[0].Title)
+		assert.Empty(t, reconciler.shrinkDetectedAt)
+	})
+
+	t.Run("should remove the last external link when it expires", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+
+		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		warpMenuConfig := config.Configuration{
+			Sources: []config.Source{{Path: "externals", Type: "externals"}},
+			Support: []config.SupportSource{{Identifier: "docsCloudoguComUrl", External: true, Href: "https://docs.cloudogu.com/"}},
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+		globalConfig := config2.CreateGlobalConfig(config2.Entries{
+			"externals/training": config2.Value(multiline(
+				`DisplayName: Training`,
+				`URL: "https://training.example.com"`,
+				`Category: News`,
+				`ValidUntil: `+time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			)),
+		})
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[],"entryCounts":{"externals:externals":1}}`}
+			}).
+			Return(nil)
+
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, NewMockDoguVersionRegistry(t), NewMockLocalDoguRepo(t), eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+
+		warpMenuCategories := parseWarpMenuCategoriesFromJsonFile(t, warpMenuPath)
+		require.Len(t, warpMenuCategories, 1)
+		assert.Equal(t, "Support", warpMenuCategories[0].Title)
+	})
+
+	t.Run("should report rejected external entries as event and in the status configmap", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		localDoguRepo := NewMockLocalDoguRepo(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+
+		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+
+		warpMenuConfig := config.Configuration{
+			Sources: []config.Source{
+				{
+					Path: "externals",
+					Type: "externals",
+				},
+			},
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+
+		globalConfig := config2.CreateGlobalConfig(config2.Entries{
+			"externals/valid": config2.Value(multiline(
+				`DisplayName: Test`,
+				`URL: "https://test.example.com"`,
+				`Category: News`,
+			)),
+			"externals/invalid": config2.Value(multiline(
+				`DisplayName: Invalid`,
+				`Category: News`,
+			)),
+		})
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
+
+		eventRecorderMock.EXPECT().Eventf(mock.Anything, v1.EventTypeWarning, rejectedWarpMenuEntryEventReason, "Global config key %q was skipped for the warp menu: %s", "externals/invalid", "could not find URL on external entry")
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpStatusConfigMap))
+		clientMock.EXPECT().
+			Create(mock.Anything, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
+				configMap := obj.(*v1.ConfigMap)
+				assert.Equal(t, config.WarpStatusConfigMap, configMap.Name)
+				assert.JSONEq(t, `{"rejectedEntries":[{"key":"externals/invalid","reason":"could not find URL on external entry"}],"entryCounts":{"externals:externals":1}}`, configMap.Data[warpMenuStatusDataKey])
+			}).
+			Return(nil)
+
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+
+		warpMenuCategories := parseWarpMenuCategoriesFromJsonFile(t, warpMenuPath)
+		require.Equal(t, 1, len(warpMenuCategories))
+		assert.Equal(t, 1, len(warpMenuCategories[0].Entries))
+		assert.Equal(t, "Test", warpMenuCategories[0].Entries[0].DisplayName)
+	})
+
+	t.Run("should render hrefs and report template errors as event and in the status configmap", func(t *testing.T) {
+		clientMock := newMockK8sClient(t)
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		doguVersionRegistryMock := NewMockDoguVersionRegistry(t)
+		localDoguRepo := NewMockLocalDoguRepo(t)
+		eventRecorderMock := newMockEventRecorder(t)
+		warpMenuPath := t.TempDir()
+
+		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+
+		warpMenuConfig := config.Configuration{
+			Sources: []config.Source{{Path: "externals", Type: "externals"}},
+			Support: []config.SupportSource{{Identifier: "scmHelp", External: true, Href: "https://{{ .scm_host }}/help"}},
+		}
+		mockExpectGetWarpMenuConfig(t, clientMock, warpMenuConfig)
+
+		globalConfig := config2.CreateGlobalConfig(config2.Entries{
+			"fqdn": "ces.example.com",
+			"externals/scm": config2.Value(multiline(
+				`DisplayName: SCM Help`,
+				`URL: "https://{{ .fqdn }}/scm/help"`,
+				`Category: Help`,
+			)),
+		})
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
+
+		eventRecorderMock.EXPECT().Eventf(mock.Anything, v1.EventTypeWarning, templateErrorEventReason, "Entry %q of source %s was skipped for the warp menu: %s", "scmHelp", "support", mock.Anything)
+		clientMock.EXPECT().
+			Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+			Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpStatusConfigMap))
+		clientMock.EXPECT().
+			Create(mock.Anything, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
+				configMap := obj.(*v1.ConfigMap)
+				assert.Contains(t, configMap.Data[warpMenuStatusDataKey], `"templateErrors":[{"source":"support","entry":"scmHelp","reason":`)
+			}).
+			Return(nil)
+
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
+
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "aConfigMap"}}
+		_, err := reconciler.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+
+		warpMenuCategories := parseWarpMenuCategoriesFromJsonFile(t, warpMenuPath)
+		require.Equal(t, 1, len(warpMenuCategories))
+		assert.Equal(t, "Help", warpMenuCategories[0].Title)
+		assert.Equal(t, "https://ces.example.com/scm/help", warpMenuCategories[0].Entries[0].Href)
+	})
+
 	t.Run("should create menu entries under category 'support' configured in the warp menu config map", func(t *testing.T) {
 		clientMock := newMockK8sClient(t)
 		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
@@ -119,6 +430,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		warpMenuPath := t.TempDir()
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Support: []config.SupportSource{
@@ -139,7 +451,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		globalConfig := config2.CreateGlobalConfig(config2.Entries{})
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -175,6 +487,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		eventRecorderMock := newMockEventRecorder(t)
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Sources: []config.Source{
@@ -213,7 +526,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		doguVersionRegistryMock.EXPECT().GetCurrentOfAll(mock.Anything).Return(doguSimpleVersionNames, nil)
 		localDoguRepo.EXPECT().GetAll(mock.Anything, doguSimpleVersionNames).Return(simpleVersionNameToDoguMap, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -257,6 +570,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		eventRecorderMock := newMockEventRecorder(t)
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Sources: []config.Source{
@@ -287,7 +601,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		doguVersionRegistryMock.EXPECT().GetCurrentOfAll(mock.Anything).Return(doguSimpleVersionNames, nil)
 		localDoguRepo.EXPECT().GetAll(mock.Anything, doguSimpleVersionNames).Return(simpleVersionNameToDoguMap, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -306,6 +620,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		eventRecorderMock := newMockEventRecorder(t)
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Support: []config.SupportSource{
@@ -328,7 +643,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		})
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -347,6 +662,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		eventRecorderMock := newMockEventRecorder(t)
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Support: []config.SupportSource{
@@ -375,7 +691,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		})
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -411,6 +727,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		eventRecorderMock := newMockEventRecorder(t)
 
 		mocksExpectWriteEvent(clientMock, eventRecorderMock)
+		mockExpectWriteStatus(clientMock)
 
 		warpMenuConfig := config.Configuration{
 			Support: []config.SupportSource{
@@ -438,7 +755,7 @@ func TestWarpMenuReconcile(t *testing.T) {
 		})
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
 
-		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName)
+		reconciler := NewWarpMenuReconciler(clientMock, globalConfigRepoMock, doguVersionRegistryMock, localDoguRepo, eventRecorderMock, warpMenuPath, testDeploymentName, testGeneratorVersion)
 
 		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "aNamespace", Name: "aConfigMap"}}
 		_, err := reconciler.Reconcile(context.Background(), request)
@@ -491,7 +808,7 @@ func parseWarpMenuCategoriesFromJsonFile(t *testing.T, warpMenuPath string) []Wa
 
 func mockExpectGetWarpMenuConfig(t *testing.T, clientMock *mockK8sClient, warpMenuConfig config.Configuration) {
 	clientMock.EXPECT().
-		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1.ConfigMap")).
+		Get(mock.Anything, types2.NamespacedName{Name: config.WarpConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
 		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
 			warpMenuConfigAsString, err := yaml.Marshal(warpMenuConfig)
 			require.NoError(t, err)
@@ -514,6 +831,15 @@ func mocksExpectWriteEvent(clientMock *mockK8sClient, eventRecorderMock *mockEve
 
 }
 
+func mockExpectWriteStatus(clientMock *mockK8sClient) {
+	clientMock.EXPECT().
+		Get(mock.Anything, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}, mock.AnythingOfType("*v1.ConfigMap")).
+		Return(k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, config.WarpStatusConfigMap))
+	clientMock.EXPECT().
+		Create(mock.Anything, mock.AnythingOfType("*v1.ConfigMap")).
+		Return(nil)
+}
+
 func findCategoryByTitle(warpMenuCategories []WarpMenuCategory, title string) (WarpMenuCategory, bool) {
 	for _, cat := range warpMenuCategories {
 		if cat.Title == title {
@@ -539,3 +865,98 @@ func newSimpleNameToDoguMap(t *testing.T, dogus []*core.Dogu) ([]dogu.SimpleName
 
 	return nameVersions, doguMap
 }
+
+func TestWarpMenuConfigReconciler_readBaseURL(t *testing.T) {
+	t.Run("should create url from fqdn", func(t *testing.T) {
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfig := config2.CreateGlobalConfig(config2.Entries{"fqdn": "ces.example.com"})
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(globalConfig, nil)
+		reconciler := &WarpMenuConfigReconciler{globalConfigRepo: globalConfigRepoMock}
+
+		assert.Equal(t, "https://ces.example.com", reconciler.readBaseURL(context.Background()))
+	})
+
+	t.Run("should return empty url without fqdn", func(t *testing.T) {
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config2.CreateGlobalConfig(config2.Entries{}), nil)
+		reconciler := &WarpMenuConfigReconciler{globalConfigRepo: globalConfigRepoMock}
+
+		assert.Equal(t, "", reconciler.readBaseURL(context.Background()))
+	})
+
+	t.Run("should return empty url on error", func(t *testing.T) {
+		globalConfigRepoMock := NewMockGlobalConfigRepository(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config2.GlobalConfig{}, assert.AnError)
+		reconciler := &WarpMenuConfigReconciler{globalConfigRepo: globalConfigRepoMock}
+
+		assert.Equal(t, "", reconciler.readBaseURL(context.Background()))
+	})
+}
+
+func Test_requeueAtNextEntryChange(t *testing.T) {
+	t.Run("should not requeue without time-limited entries", func(t *testing.T) {
+		assert.Equal(t, ctrl.Result{}, requeueAtNextEntryChange(nil))
+	})
+
+	t.Run("should requeue at next entry change", func(t *testing.T) {
+		nextEntryChange := time.Now().Add(time.Hour)
+
+		result := requeueAtNextEntryChange(&nextEntryChange)
+
+		assert.InDelta(t, time.Hour, result.RequeueAfter, float64(time.Minute))
+	})
+
+	t.Run("should requeue after minimum delay if change passed", func(t *testing.T) {
+		nextEntryChange := time.Now().Add(-time.Second)
+
+		result := requeueAtNextEntryChange(&nextEntryChange)
+
+		assert.Equal(t, minEntryChangeRequeueDelay, result.RequeueAfter)
+	})
+}
+
+func TestWarpMenuConfigReconciler_recordRejectedEntries(t *testing.T) {
+	t.Run("should only record new rejected entries", func(t *testing.T) {
+		// given
+		deployment := &appsv1.Deployment{}
+		invalid := RejectedEntry{Key: "externals/invalid", Reason: "could not find URL on external entry"}
+		broken := RejectedEntry{Key: "externals/broken", Reason: "failed to unmarshal external entry"}
+		eventRecorderMock := newMockEventRecorder(t)
+		eventRecorderMock.EXPECT().Eventf(deployment, v1.EventTypeWarning, rejectedWarpMenuEntryEventReason, "Global config key %q was skipped for the warp menu: %s", invalid.Key, invalid.Reason).Twice()
+		eventRecorderMock.EXPECT().Eventf(deployment, v1.EventTypeWarning, rejectedWarpMenuEntryEventReason, "Global config key %q was skipped for the warp menu: %s", broken.Key, broken.Reason).Once()
+		reconciler := &WarpMenuConfigReconciler{eventRecorder: eventRecorderMock}
+
+		// when
+		reconciler.recordRejectedEntries(deployment, testNamespace, []RejectedEntry{invalid})
+		reconciler.recordRejectedEntries(deployment, testNamespace, []RejectedEntry{invalid, broken})
+		reconciler.recordRejectedEntries(deployment, testNamespace, []RejectedEntry{broken})
+		reconciler.recordRejectedEntries(deployment, testNamespace, nil)
+		reconciler.recordRejectedEntries(deployment, testNamespace, []RejectedEntry{invalid})
+
+		// then
+		assert.Equal(t, []RejectedEntry{invalid}, reconciler.reportedRejectedEntries[testNamespace])
+	})
+}
+
+func TestWarpMenuConfigReconciler_recordTemplateErrors(t *testing.T) {
+	t.Run("should only record new template errors", func(t *testing.T) {
+		// given
+		deployment := &appsv1.Deployment{}
+		scmErr := TemplateError{Source: "support", Entry: "scmHelp", Reason: "map has no entry for key \"scm_host\""}
+		docsErr := TemplateError{Source: "externals:externals", Entry: "docs", Reason: "map has no entry for key \"docs_host\""}
+		eventRecorderMock := newMockEventRecorder(t)
+		eventRecorderMock.EXPECT().Eventf(deployment, v1.EventTypeWarning, templateErrorEventReason, "Entry %q of source %s was skipped for the warp menu: %s", "scmHelp", "support", scmErr.Reason).Twice()
+		eventRecorderMock.EXPECT().Eventf(deployment, v1.EventTypeWarning, templateErrorEventReason, "Entry %q of source %s was skipped for the warp menu: %s", "docs", "externals:externals", docsErr.Reason).Once()
+		reconciler := &WarpMenuConfigReconciler{eventRecorder: eventRecorderMock}
+
+		// when
+		reconciler.recordTemplateErrors(deployment, testNamespace, []TemplateError{scmErr})
+		reconciler.recordTemplateErrors(deployment, testNamespace, []TemplateError{scmErr, docsErr})
+		reconciler.recordTemplateErrors(deployment, testNamespace, []TemplateError{docsErr})
+		reconciler.recordTemplateErrors(deployment, testNamespace, nil)
+		reconciler.recordTemplateErrors(deployment, testNamespace, []TemplateError{scmErr})
+
+		// then
+		assert.Equal(t, []TemplateError{scmErr}, reconciler.reportedTemplateErrors[testNamespace])
+	})
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/cloudogu/warp-assets/controller/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// warpMenuFileMode allows nginx to read the generated files.
+const warpMenuFileMode = 0644
+
+// writeWarpMenuFile writes the menu.json with its compressed copies and keeps a copy of it as last known good menu. If
+// the generated menu is invalid, the last known good menu is restored and an error is returned.
+func (r *WarpMenuConfigReconciler) writeWarpMenuFile(categories types.Categories, menuFormat string, compression config.CompressionConfig) error {
+	format, err := types.ParseMenuFormat(menuFormat)
+	if err != nil {
+		ctrl.Log.Info(fmt.Sprintf("invalid menu format, using %s: %s", types.MenuFormatV1, err.Error()))
+		format = types.MenuFormatV1
+	}
+
+	jsonData, err := r.marshalWarpMenu(menuJsonFileName, categories, format)
+	if err != nil {
+		return fmt.Errorf("failed to marshal warp data: %w", err)
+	}
+
+	if err = types.ValidateMenu(jsonData, format); err != nil {
+		if restoreErr := r.restoreLastGoodWarpMenu(compression); restoreErr != nil {
+			return errors.Join(fmt.Errorf("generated warp menu is invalid: %w", err), restoreErr)
+		}
+		return fmt.Errorf("generated warp menu is invalid, restored last known good menu: %w", err)
+	}
+
+	if err = r.writeCompressedFile(menuJsonFileName, jsonData, compression); err != nil {
+		return err
+	}
+	return r.writeFile(menuLastGoodFileName, jsonData)
+}
+
+// marshalWarpMenu marshals the categories in the given format. A v2 menu keeps the generation time of the existing file
+// with the given name if its categories did not change, so unchanged menus are neither written nor published again.
+func (r *WarpMenuConfigReconciler) marshalWarpMenu(name string, categories types.Categories, format types.MenuFormat) ([]byte, error) {
+	if format == types.MenuFormatV2 {
+		menu, err := types.NewMenu(categories, time.Now(), r.generatorVersion)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := r.readWarpMenu(name); ok && existing.Hash == menu.Hash {
+			menu.GeneratedAt = existing.GeneratedAt
+		}
+		return json.Marshal(menu)
+	}
+
+	// the legacy warp menu script does not support child categories
+	return json.Marshal(categories.Flatten())
+}
+
+// readWarpMenu reads the existing v2 menu with the given name. False is returned if there is none.
+func (r *WarpMenuConfigReconciler) readWarpMenu(name string) (types.Menu, bool) {
+	data, err := os.ReadFile(filepath.Join(r.warpMenuPath, name))
+	if err != nil {
+		return types.Menu{}, false
+	}
+
+	var menu types.Menu
+	if err = json.Unmarshal(data, &menu); err != nil || menu.SchemaVersion != types.MenuSchemaVersion {
+		return types.Menu{}, false
+	}
+	return menu, true
+}
+
+// restoreLastGoodWarpMenu replaces the menu.json with the last known good menu, if there is one.
+func (r *WarpMenuConfigReconciler) restoreLastGoodWarpMenu(compression config.CompressionConfig) error {
+	lastGood, err := os.ReadFile(filepath.Join(r.warpMenuPath, menuLastGoodFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read last known good warp menu: %w", err)
+	}
+
+	if err = r.writeCompressedFile(menuJsonFileName, lastGood, compression); err != nil {
+		return fmt.Errorf("failed to restore last known good warp menu: %w", err)
+	}
+	return nil
+}
+
+// writeFile replaces the file atomically, so nginx never serves a partially written file. The file is not touched
+// if its content did not change.
+func (r *WarpMenuConfigReconciler) writeFile(name string, data []byte) error {
+	path := filepath.Join(r.warpMenuPath, name)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+
+	file, err := os.CreateTemp(r.warpMenuPath, "."+name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+	tempPath := file.Name()
+	defer func() {
+		// the temporary file does not exist anymore if it was renamed successfully
+		_ = os.Remove(tempPath)
+	}()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write data to %s: %w", tempPath, err)
+	}
+
+	if err = os.Chmod(tempPath, warpMenuFileMode); err != nil {
+		return fmt.Errorf("failed to set permissions of %s: %w", tempPath, err)
+	}
+	if err = os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
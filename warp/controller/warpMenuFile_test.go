@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/cloudogu/warp-assets/controller/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validCategories = types.Categories{
+	{Title: "Links", Entries: types.Entries{{DisplayName: "Docs", Href: "https://docs.cloudogu.com", Target: types.TARGET_EXTERNAL}}},
+}
+
+func TestWarpMenuConfigReconciler_writeWarpMenuFile(t *testing.T) {
+	t.Run("should write menu and last known good menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+
+		// when
+		err := reconciler.writeWarpMenuFile(validCategories, "", config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		menu, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.json"))
+		require.NoError(t, err)
+		lastGood, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.last-good.json"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"Title":"Links","Order":0,"Entries":[{"DisplayName":"Docs","Href":"https://docs.cloudogu.com","Title":"","Target":"external"}]}]`, string(menu))
+		assert.Equal(t, menu, lastGood)
+	})
+
+	t.Run("should keep generation time of unchanged v2 menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		require.NoError(t, reconciler.writeWarpMenuFile(validCategories, "v2", config.CompressionConfig{}))
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+		first, err := os.ReadFile(path)
+		require.NoError(t, err)
+		modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		// when
+		err = reconciler.writeWarpMenuFile(validCategories, "v2", config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		second, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, info.ModTime().Equal(modTime))
+	})
+
+	t.Run("should update generation time of changed v2 menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+		generatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		oldMenu, err := types.NewMenu(types.Categories{{Title: "Other", Entries: types.Entries{}}}, generatedAt, "")
+		require.NoError(t, err)
+		oldData, err := json.Marshal(oldMenu)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, oldData, 0644))
+
+		// when
+		err = reconciler.writeWarpMenuFile(validCategories, "v2", config.CompressionConfig{})
+
+		// then
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var menu types.Menu
+		require.NoError(t, json.Unmarshal(data, &menu))
+		assert.True(t, menu.GeneratedAt.After(generatedAt))
+	})
+
+	t.Run("should restore last known good menu if generated menu is invalid", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		require.NoError(t, reconciler.writeWarpMenuFile(validCategories, "", config.CompressionConfig{}))
+		lastGood, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.last-good.json"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(reconciler.warpMenuPath, "menu.json"), []byte("[{"), 0644))
+		invalidCategories := types.Categories{{Title: "Support", Entries: types.Entries{{Title: "about", Target: types.TARGET_SELF}}}}
+
+		// when
+		err = reconciler.writeWarpMenuFile(invalidCategories, "", config.CompressionConfig{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "generated warp menu is invalid, restored last known good menu")
+		assert.ErrorContains(t, err, "entry \"\" in category \"Support\" has no href")
+		menu, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.json"))
+		require.NoError(t, err)
+		assert.Equal(t, lastGood, menu)
+	})
+
+	t.Run("should not write invalid menu without last known good menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		invalidCategories := types.Categories{{Title: "Support", Entries: types.Entries{{Title: "about", Target: types.TARGET_SELF}}}}
+
+		// when
+		err := reconciler.writeWarpMenuFile(invalidCategories, "", config.CompressionConfig{})
+
+		// then
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(reconciler.warpMenuPath, "menu.json"))
+	})
+
+	t.Run("should fail to restore unreadable last known good menu", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		require.NoError(t, os.Mkdir(filepath.Join(reconciler.warpMenuPath, "menu.last-good.json"), 0755))
+		invalidCategories := types.Categories{{Title: "Support", Entries: types.Entries{{Title: "about", Target: types.TARGET_SELF}}}}
+
+		// when
+		err := reconciler.writeWarpMenuFile(invalidCategories, "", config.CompressionConfig{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "generated warp menu is invalid")
+		assert.ErrorContains(t, err, "failed to read last known good warp menu")
+	})
+}
+
+func TestWarpMenuConfigReconciler_writeFile(t *testing.T) {
+	t.Run("should replace file without leaving temporary files", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		require.NoError(t, os.WriteFile(filepath.Join(reconciler.warpMenuPath, "menu.json"), []byte("old"), 0600))
+
+		// when
+		err := reconciler.writeFile("menu.json", []byte("new"))
+
+		// then
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(reconciler.warpMenuPath, "menu.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+		info, err := os.Stat(filepath.Join(reconciler.warpMenuPath, "menu.json"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+		files, err := os.ReadDir(reconciler.warpMenuPath)
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("should not touch unchanged file", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: t.TempDir()}
+		path := filepath.Join(reconciler.warpMenuPath, "menu.json")
+		require.NoError(t, os.WriteFile(path, []byte("same"), 0644))
+		modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		// when
+		err := reconciler.writeFile("menu.json", []byte("same"))
+
+		// then
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, modTime, info.ModTime())
+	})
+
+	t.Run("should fail if directory does not exist", func(t *testing.T) {
+		// given
+		reconciler := &WarpMenuConfigReconciler{warpMenuPath: filepath.Join(t.TempDir(), "missing")}
+
+		// when
+		err := reconciler.writeFile("menu.json", []byte("data"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to create temporary file")
+	})
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudogu/warp-assets/config"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types2 "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const warpMenuStatusDataKey = "status.json"
+
+// RejectedEntry describes a global config key that could not be converted into a warp menu entry.
+type RejectedEntry struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// WarpMenuStatus is the machine-readable result of the last warp menu generation.
+type WarpMenuStatus struct {
+	RejectedEntries []RejectedEntry `json:"rejectedEntries"`
+	// EntryCounts contains the number of entries read from each source. It is the baseline of the shrink guard.
+	EntryCounts map[string]int `json:"entryCounts,omitempty"`
+	// StaleSources contains the sources whose last successful result is used because they could not be read.
+	StaleSources []StaleSource `json:"staleSources,omitempty"`
+	// TemplateErrors contains the entries whose href or description could not be rendered.
+	TemplateErrors []TemplateError `json:"templateErrors,omitempty"`
+	// NextEntryChange is the next time at which a time-limited entry appears or disappears.
+	NextEntryChange *time.Time `json:"nextEntryChange,omitempty"`
+}
+
+// readStatus returns the status of the last warp menu generation. An empty status is returned if there is none or if
+// it cannot be parsed.
+func (r *WarpMenuConfigReconciler) readStatus(ctx context.Context, namespace string) (WarpMenuStatus, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: namespace}, configMap)
+	if k8serrors.IsNotFound(err) {
+		return WarpMenuStatus{}, nil
+	}
+	if err != nil {
+		return WarpMenuStatus{}, fmt.Errorf("failed to get warp menu status configmap: %w", err)
+	}
+
+	status := WarpMenuStatus{}
+	statusJson, ok := configMap.Data[warpMenuStatusDataKey]
+	if !ok {
+		return status, nil
+	}
+	if err = json.Unmarshal([]byte(statusJson), &status); err != nil {
+		ctrl.Log.Info(fmt.Sprintf("ignoring invalid warp menu status: %s", err.Error()))
+		return WarpMenuStatus{}, nil
+	}
+
+	return status, nil
+}
+
+// writeStatus stores the status in the status configmap. The configmap is created if it does not exist and only
+// updated if its content changed.
+func (r *WarpMenuConfigReconciler) writeStatus(ctx context.Context, namespace string, status WarpMenuStatus) error {
+	if status.RejectedEntries == nil {
+		status.RejectedEntries = []RejectedEntry{}
+	}
+
+	statusJson, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal warp menu status: %w", err)
+	}
+
+	return r.writeConfigMapData(ctx, namespace, config.WarpStatusConfigMap, warpMenuStatusDataKey, string(statusJson), "warp menu status")
+}
+
+// writeConfigMapData stores the value in the configmap. The configmap is created if it does not exist and only
+// updated if the value changed.
+func (r *WarpMenuConfigReconciler) writeConfigMapData(ctx context.Context, namespace string, name string, key string, value string, description string) error {
+	configMap := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types2.NamespacedName{Name: name, Namespace: namespace}, configMap)
+	if k8serrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "ces"},
+			},
+			Data: map[string]string{key: value},
+		}
+		if err = r.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create %s configmap: %w", description, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s configmap: %w", description, err)
+	}
+
+	if configMap.Data[key] == value {
+		return nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = value
+	if err = r.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update %s configmap: %w", description, err)
+	}
+
+	return nil
+}
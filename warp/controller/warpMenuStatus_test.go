@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/warp-assets/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	types2 "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWarpMenuConfigReconciler_writeStatus(t *testing.T) {
+	statusKey := types2.NamespacedName{Name: config.WarpStatusConfigMap, Namespace: testNamespace}
+
+	t.Run("should update existing status configmap", func(t *testing.T) {
+		// given
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[]}`}
+			}).
+			Return(nil)
+		clientMock.EXPECT().Update(testCtx, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) {
+				assert.JSONEq(t, `{"rejectedEntries":[{"key":"externals/a","reason":"broken"}]}`, obj.(*v1.ConfigMap).Data[warpMenuStatusDataKey])
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		// when
+		err := reconciler.writeStatus(testCtx, testNamespace, WarpMenuStatus{RejectedEntries: []RejectedEntry{{Key: "externals/a", Reason: "broken"}}})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not update unchanged status configmap", func(t *testing.T) {
+		// given
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).
+			Run(func(ctx context.Context, key types2.NamespacedName, obj client.Object, opts ...client.GetOption) {
+				obj.(*v1.ConfigMap).Data = map[string]string{warpMenuStatusDataKey: `{"rejectedEntries":[]}`}
+			}).
+			Return(nil)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		// when
+		err := reconciler.writeStatus(testCtx, testNamespace, WarpMenuStatus{})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail to get status configmap", func(t *testing.T) {
+		// given
+		clientMock := newMockK8sClient(t)
+		clientMock.EXPECT().Get(testCtx, statusKey, mock.AnythingOfType("*v1.ConfigMap")).Return(assert.AnError)
+		reconciler := &WarpMenuConfigReconciler{client: clientMock}
+
+		// when
+		err := reconciler.writeStatus(testCtx, testNamespace, WarpMenuStatus{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get warp menu status configmap")
+	})
+}
@@ -25,6 +25,8 @@ import (
 )
 
 var (
+	// Version of the application, set by the build.
+	Version              = "development"
 	scheme               = runtime.NewScheme()
 	logger               = ctrl.Log.WithName("k8s-ces.assets.warp.main")
 	metricsAddr          string
@@ -87,7 +89,8 @@ func setupWarpMenuReconciler(warpMenuManager k8sManager, watchNamespace string)
 
 	client := warpMenuManager.GetClient()
 	configMapInterface := clientset.CoreV1().ConfigMaps(watchNamespace)
-	globalConfigRepo := repository.NewGlobalConfigRepository(configMapInterface)
+	// the global config is cached from the registry watch of the controller
+	globalConfigRepo := warpCtrl.NewCachedGlobalConfigRepository(repository.NewGlobalConfigRepository(configMapInterface))
 	doguVersionRegistry := dogu.NewDoguVersionRegistry(configMapInterface)
 	localDoguRepo := dogu.NewLocalDoguDescriptorRepository(configMapInterface)
 
@@ -101,12 +104,28 @@ func setupWarpMenuReconciler(warpMenuManager k8sManager, watchNamespace string)
 	if err != nil {
 		return fmt.Errorf("read config value 'warp path': %w", err)
 	}
-	reconciler := warpCtrl.NewWarpMenuReconciler(client, globalConfigRepo, doguVersionRegistry, localDoguRepo, eventRecorder, warpMenuPath, deploymentName)
-	err = reconciler.SetupWithManager(warpMenuManager)
+	reconciler := warpCtrl.NewWarpMenuReconciler(client, globalConfigRepo, doguVersionRegistry, localDoguRepo, eventRecorder, warpMenuPath, deploymentName, Version)
+	debounceWindow, err := config.ReadDebounceWindow()
+	if err != nil {
+		return fmt.Errorf("read config value 'debounce window': %w", err)
+	}
+	debounceMaxDelay, err := config.ReadDebounceMaxDelay()
+	if err != nil {
+		return fmt.Errorf("read config value 'debounce max delay': %w", err)
+	}
+	err = reconciler.SetupWithManager(warpMenuManager, watchNamespace, debounceWindow, debounceMaxDelay)
 	if err != nil {
 		return fmt.Errorf("setup reconciler with manager: %w", err)
 	}
 
+	if menuAddr := config.ReadMenuBindAddress(); menuAddr != config.MenuBindAddressDisabled {
+		menuServer := warpCtrl.NewMenuServer(menuAddr)
+		reconciler.SetMenuServer(menuServer)
+		if err = warpMenuManager.Add(menuServer); err != nil {
+			return fmt.Errorf("add warp menu server to manager: %w", err)
+		}
+	}
+
 	return nil
 }
 